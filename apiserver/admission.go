@@ -0,0 +1,104 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	admissionmetrics "k8s.io/apiserver/pkg/admission/metrics"
+)
+
+// AdmissionFunc is called by typed admission plugins with the object and the old object of a request.
+// Objects are of the type passed to Resource, old is nil on create and obj is nil on delete.
+type AdmissionFunc[T runtime.Object] func(ctx context.Context, obj, old T, a admission.Attributes) error
+
+// AdmissionPlugin is an admission plugin registered with Builder.WithAdmission, see ValidatingAdmission
+// and MutatingAdmission.
+type AdmissionPlugin struct {
+	name   string
+	plugin admission.Interface
+}
+
+// ValidatingAdmission returns an admission plugin that validates requests for objects of type T with fn,
+// e.g. against other objects. Requests are rejected if fn returns an error, errors that are no API status
+// errors are returned as forbidden. The plugin handles the given operations, or create, update and delete
+// if none are given. name identifies the plugin in the admission metrics.
+func ValidatingAdmission[T runtime.Object](name string, fn AdmissionFunc[T], operations ...admission.Operation) AdmissionPlugin {
+	return AdmissionPlugin{name: name, plugin: &validatingAdmission[T]{typedAdmission: newTypedAdmission(fn, operations)}}
+}
+
+// MutatingAdmission returns an admission plugin that mutates the object of requests for objects of type T
+// with fn, e.g. to set fields depending on the requesting user. Mutating plugins run before all validating
+// ones. Requests are rejected if fn returns an error, like with ValidatingAdmission.
+func MutatingAdmission[T runtime.Object](name string, fn AdmissionFunc[T], operations ...admission.Operation) AdmissionPlugin {
+	return AdmissionPlugin{name: name, plugin: &mutatingAdmission[T]{typedAdmission: newTypedAdmission(fn, operations)}}
+}
+
+// typedAdmission calls fn for requests with objects of type T.
+type typedAdmission[T runtime.Object] struct {
+	*admission.Handler
+	fn AdmissionFunc[T]
+}
+
+func newTypedAdmission[T runtime.Object](fn AdmissionFunc[T], operations []admission.Operation) typedAdmission[T] {
+	if len(operations) == 0 {
+		operations = []admission.Operation{admission.Create, admission.Update, admission.Delete}
+	}
+	return typedAdmission[T]{Handler: admission.NewHandler(operations...), fn: fn}
+}
+
+// admit calls fn if the object or the old object of the request is of type T, e.g. not for the scale
+// subresource.
+func (p typedAdmission[T]) admit(ctx context.Context, a admission.Attributes) error {
+	obj, objOk := a.GetObject().(T)
+	old, oldOk := a.GetOldObject().(T)
+	if !objOk && !oldOk {
+		return nil
+	}
+	if err := p.fn(ctx, obj, old, a); err != nil {
+		if _, ok := err.(apierrors.APIStatus); ok {
+			return err
+		}
+		return admission.NewForbidden(a, err)
+	}
+	return nil
+}
+
+type validatingAdmission[T runtime.Object] struct {
+	typedAdmission[T]
+}
+
+var _ admission.ValidationInterface = &validatingAdmission[runtime.Object]{}
+
+// Validate implements admission.ValidationInterface.
+func (p *validatingAdmission[T]) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	return p.admit(ctx, a)
+}
+
+type mutatingAdmission[T runtime.Object] struct {
+	typedAdmission[T]
+}
+
+var _ admission.MutationInterface = &mutatingAdmission[runtime.Object]{}
+
+// Admit implements admission.MutationInterface.
+func (p *mutatingAdmission[T]) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	return p.admit(ctx, a)
+}
+
+// newAdmissionChain returns the admission chain of the typed plugins followed by the admission plugins of the
+// options in control, which may be nil.
+func newAdmissionChain(plugins []AdmissionPlugin, control admission.Interface) admission.Interface {
+	handlers := make([]admission.Interface, 0, len(plugins)+1)
+	for _, p := range plugins {
+		handlers = append(handlers, admissionmetrics.WithControllerMetrics(p.plugin, p.name))
+	}
+	if control != nil {
+		handlers = append(handlers, control)
+	}
+	return admission.NewChainHandler(handlers...)
+}
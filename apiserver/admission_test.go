@@ -0,0 +1,103 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = Describe("Admission", func() {
+	var (
+		ctx     context.Context
+		widgets dynamic.ResourceInterface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		b := newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)).WithAdmission(
+			MutatingAdmission("widget-version", func(_ context.Context, obj, _ *Widget, a admission.Attributes) error {
+				if obj.Labels == nil {
+					obj.Labels = map[string]string{}
+				}
+				obj.Labels["version"] = a.GetResource().Version
+				return nil
+			}, admission.Create),
+			ValidatingAdmission("widget-replicas", func(_ context.Context, obj, old *Widget, _ admission.Attributes) error {
+				if old != nil && obj.Spec.Replicas != old.Spec.Replicas {
+					return errors.New("replicas must be changed through the scale subresource")
+				}
+				return nil
+			}, admission.Update),
+			ValidatingAdmission("widget-protection", func(_ context.Context, obj, old *Widget, _ admission.Attributes) error {
+				if obj == nil && old.Labels["protected"] == "true" {
+					return apierrors.NewInvalid(old.GroupVersionKind().GroupKind(), old.Name, field.ErrorList{
+						field.Forbidden(field.NewPath("metadata", "labels", "protected"), "protected widgets cannot be deleted"),
+					})
+				}
+				return nil
+			}),
+		)
+		server := startTestServer(b)
+		DeferCleanup(server.Stop)
+		widgets = widgetClient(server)
+	})
+
+	It("should mutate objects with mutating admission plugins", func() {
+		created, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created.GetLabels()).To(HaveKeyWithValue("version", testGroupVersion.Version))
+	})
+
+	It("should reject requests with validating admission plugins", func() {
+		created, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(created.Object, int64(3), "spec", "replicas")).To(Succeed())
+		_, err = widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).To(Satisfy(apierrors.IsForbidden))
+		Expect(err).To(MatchError(ContainSubstring("replicas must be changed through the scale subresource")))
+
+		// The plugins only see widgets, not the scale of the subresource.
+		scale, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(scale.Object, int64(3), "spec", "replicas")).To(Succeed())
+		_, err = widgets.Update(ctx, scale, metav1.UpdateOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should pass objects on delete and keep API status errors", func() {
+		obj := newWidget("foo", 1)
+		obj.SetLabels(map[string]string{"protected": "true"})
+		_, err := widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		err = widgets.Delete(ctx, "foo", metav1.DeleteOptions{})
+		Expect(err).To(Satisfy(apierrors.IsInvalid))
+		Expect(err).To(MatchError(ContainSubstring("protected widgets cannot be deleted")))
+
+		_, err = widgets.Create(ctx, newWidget("bar", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(widgets.Delete(ctx, "bar", metav1.DeleteOptions{})).To(Succeed())
+	})
+
+	It("should only handle the given operations", func() {
+		fn := func(context.Context, *Widget, *Widget, admission.Attributes) error { return nil }
+		p := ValidatingAdmission("validating", fn).plugin
+		Expect(p.Handles(admission.Create)).To(BeTrue())
+		Expect(p.Handles(admission.Delete)).To(BeTrue())
+		Expect(p.Handles(admission.Connect)).To(BeFalse())
+		p = MutatingAdmission("mutating", fn, admission.Update).plugin
+		Expect(p.Handles(admission.Create)).To(BeFalse())
+		Expect(p.Handles(admission.Update)).To(BeTrue())
+	})
+})
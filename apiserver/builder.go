@@ -4,20 +4,34 @@
 package apiserver
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"path"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opendefense.cloud/kit/apiserver/etcd"
 	"go.opendefense.cloud/kit/apiserver/rest"
+	"go.opendefense.cloud/kit/apiserver/standalone"
+	kitstorage "go.opendefense.cloud/kit/apiserver/storage"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/endpoints"
 	"k8s.io/apiserver/pkg/endpoints/openapi"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/apiserver/pkg/util/compatibility"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -29,12 +43,26 @@ import (
 	netutils "k8s.io/utils/net"
 )
 
+// defaultEtcdPathPrefix is the default etcd key prefix, below which every API group gets its own prefix.
+const defaultEtcdPathPrefix = "/registry"
+
+// defaultBinaryVersion is the component version used if none is set with WithBinaryVersion.
+const defaultBinaryVersion = "1.2"
+
 // ExtraAdmissionInitializers is a callback that returns a SharedInformerFactory and admission plugin initializers.
 type ExtraAdmissionInitializers func(*genericapiserver.RecommendedConfig) (SharedInformerFactory, []admission.PluginInitializer, error)
 
 // RecommendedConfigFn is a callback that modifies the RecommendedConfig before the server starts.
 type RecommendedConfigFn func(*genericapiserver.RecommendedConfig)
 
+// PostStartHookFunc is called once the server serves requests. The hook context carries the loopback
+// client config to call the server itself and is done when the server shuts down.
+type PostStartHookFunc func(ctx genericapiserver.PostStartHookContext, c *genericapiserver.CompletedConfig) error
+
+// PreShutdownHookFunc is called when the server starts to shut down, before it stops serving requests.
+// The loopback client config to call the server itself is available as c.LoopbackClientConfig.
+type PreShutdownHookFunc func(c *genericapiserver.CompletedConfig) error
+
 // SharedInformerFactory is used to start informer watching for resource changes.
 type SharedInformerFactory interface {
 	// Start begins watching resources and blocks until stopCh is closed.
@@ -54,11 +82,40 @@ type Builder struct {
 	groupVersions                          []schema.GroupVersion
 	skipDefaultComponentGlobalsRegistrySet bool
 	extraAdmissionInitializers             ExtraAdmissionInitializers
+	admissionPlugins                       []AdmissionPlugin
 	sharedInformerFactories                []SharedInformerFactory
 	recommendedOptions                     *genericoptions.RecommendedOptions
 	componentGlobalsRegistry               basecompatibility.ComponentGlobalsRegistry
 	recommendedConfigFns                   []RecommendedConfigFn
 	apiGroupFns                            []APIGroupFn
+	storagePrefixes                        map[string]string
+	featureGates                           map[featuregate.Feature]featuregate.VersionedSpecs
+	binaryVersion                          string
+	minCompatibilityVersion                string
+	emulationVersionMapping                basecompatibility.VersionMapping
+	storageBackend                         string
+	sqlitePath                             string
+	embeddedEtcd                           *etcd.Options
+	etcdServersOverrides                   []string
+	watchCacheSizes                        []string
+	encryptionProviderConfig               string
+	postStartHooks                         []namedHook[PostStartHookFunc]
+	preShutdownHooks                       []namedHook[PreShutdownHookFunc]
+	healthzChecks                          []healthz.HealthChecker
+	livezChecks                            []healthz.HealthChecker
+	readyzChecks                           []healthz.HealthChecker
+	standalone                             *standalone.Options
+	longRunningSubResources                sets.Set[string]
+	selectableFields                       map[string][]string
+	multiVersionResources                  []ResourceHandler
+	resources                              []ResourceHandler
+	garbageCollector                       bool
+}
+
+// namedHook is a lifecycle hook with the name it is registered with.
+type namedHook[T any] struct {
+	name string
+	fn   T
 }
 
 // NewBuilder creates a new API server builder with the given runtime scheme.
@@ -69,6 +126,11 @@ func NewBuilder(scheme *runtime.Scheme) *Builder {
 		sharedInformerFactories: []SharedInformerFactory{},
 		apiGroupFns:             []APIGroupFn{},
 		groupVersions:           []schema.GroupVersion{},
+		storagePrefixes:         map[string]string{},
+		featureGates:            map[featuregate.Feature]featuregate.VersionedSpecs{},
+		binaryVersion:           defaultBinaryVersion,
+		longRunningSubResources: sets.New[string](),
+		selectableFields:        map[string][]string{},
 	}
 }
 
@@ -78,8 +140,44 @@ func (b *Builder) WithComponentName(n string) *Builder {
 	return b
 }
 
+// WithBinaryVersion sets the version of the component binary, e.g. "1.4".
+// It is the default emulation version and the version feature gate specs are evaluated at.
+func (b *Builder) WithBinaryVersion(v string) *Builder {
+	b.binaryVersion = v
+	return b
+}
+
+// WithMinCompatibilityVersion sets the oldest component version that can be set
+// with --emulated-version and --min-compatibility-version.
+func (b *Builder) WithMinCompatibilityVersion(v string) *Builder {
+	b.minCompatibilityVersion = v
+	return b
+}
+
+// WithEmulationVersionMapping sets the mapping from component versions to kube versions,
+// which determines the kube emulation version for a given --emulated-version.
+// By default the binary version maps to the kube version the server is built with,
+// and older minor versions map to correspondingly older kube versions.
+func (b *Builder) WithEmulationVersionMapping(fn basecompatibility.VersionMapping) *Builder {
+	b.emulationVersionMapping = fn
+	return b
+}
+
+// WithComponentGlobalsRegistry sets the registry holding the effective versions and feature gates of the component.
+// It defaults to compatibility.DefaultComponentGlobalsRegistry; a dedicated registry allows to build several
+// servers in one process, e.g. in tests.
+func (b *Builder) WithComponentGlobalsRegistry(r basecompatibility.ComponentGlobalsRegistry) *Builder {
+	b.componentGlobalsRegistry = r
+	return b
+}
+
 // WithOpenAPIDefinitions configures OpenAPI (Swagger) documentation for the API server.
-func (b *Builder) WithOpenAPIDefinitions(name, version string, defs openapicommon.GetOpenAPIDefinitions) *Builder {
+// The selectable fields of resources are published in the x-kubernetes-selectable-fields extension
+// of their definitions, like for CustomResourceDefinitions.
+func (b *Builder) WithOpenAPIDefinitions(name, version string, getDefs openapicommon.GetOpenAPIDefinitions) *Builder {
+	defs := func(ref openapicommon.ReferenceCallback) map[string]openapicommon.OpenAPIDefinition {
+		return b.withSelectableFields(getDefs(ref))
+	}
 	b.recommendedConfigFns = append(b.recommendedConfigFns, func(config *genericapiserver.RecommendedConfig) {
 		config.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(defs, openapi.NewDefinitionNamer(b.scheme))
 		config.OpenAPIConfig.Info.Title = name
@@ -92,6 +190,23 @@ func (b *Builder) WithOpenAPIDefinitions(name, version string, defs openapicommo
 	return b
 }
 
+// withSelectableFields adds the selectable fields of the registered resources to their definitions.
+func (b *Builder) withSelectableFields(defs map[string]openapicommon.OpenAPIDefinition) map[string]openapicommon.OpenAPIDefinition {
+	for name, fields := range b.selectableFields {
+		def, ok := defs[name]
+		if !ok {
+			continue
+		}
+		selectableFields := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			selectableFields = append(selectableFields, map[string]interface{}{"fieldPath": field})
+		}
+		def.Schema.AddExtension(endpoints.RouteMetaSelectableFields, selectableFields)
+		defs[name] = def
+	}
+	return defs
+}
+
 // WithAPIGroupFn registers an APIGroupFn to install an API group into the server.
 func (b *Builder) WithAPIGroupFn(fn APIGroupFn) *Builder {
 	if fn == nil {
@@ -102,11 +217,86 @@ func (b *Builder) WithAPIGroupFn(fn APIGroupFn) *Builder {
 }
 
 // With registers a ResourceHandler's API group and group versions.
+// Resources gated by features are only installed if all of their features are enabled.
+// The storage overrides of the handler default --etcd-servers-overrides and --watch-cache-sizes.
 func (b *Builder) With(rh ResourceHandler) *Builder {
-	_ = b.WithAPIGroupFn(rh.apiGroupFn)
+	if len(rh.etcdServers) > 0 {
+		b.etcdServersOverrides = append(b.etcdServersOverrides,
+			fmt.Sprintf("%s/%s#%s", rh.groupResource.Group, rh.groupResource.Resource, strings.Join(rh.etcdServers, ";")))
+	}
+	if rh.watchCacheSize != nil {
+		b.watchCacheSizes = append(b.watchCacheSizes, fmt.Sprintf("%s#%d", rh.groupResource.String(), *rh.watchCacheSize))
+	}
+	for name, fields := range rh.selectableFields {
+		b.selectableFields[name] = fields
+	}
+
+	if len(rh.versions) > 0 {
+		b.multiVersionResources = append(b.multiVersionResources, rh)
+	}
+	b.resources = append(b.resources, rh)
+
+	fn := rh.withSubResources(rh.withVersions(rh.apiGroupFn), b.longRunningSubResources)
+	if len(rh.featureGates) > 0 {
+		installFn := fn
+		fn = func(scheme *runtime.Scheme, codecs serializer.CodecFactory, c *genericapiserver.CompletedConfig) genericapiserver.APIGroupInfo {
+			if !b.featuresEnabled(rh.featureGates) {
+				return genericapiserver.APIGroupInfo{}
+			}
+			return installFn(scheme, codecs, c)
+		}
+	}
+	_ = b.WithAPIGroupFn(fn)
 	return b.WithGroupVersions(rh.groupVersions...)
 }
 
+// WithFeatureGates adds versioned feature specifications to the component's feature gate.
+// Features can be toggled with --feature-gates and are checked at request time with rest.FeatureEnabled.
+func (b *Builder) WithFeatureGates(features map[featuregate.Feature]featuregate.VersionedSpecs) *Builder {
+	for feature, specs := range features {
+		b.featureGates[feature] = specs
+	}
+	return b
+}
+
+// FeatureGate returns the feature gate of the component. It is nil until Execute registered the component.
+func (b *Builder) FeatureGate() featuregate.FeatureGate {
+	if b.componentGlobalsRegistry == nil {
+		return nil
+	}
+	return b.componentGlobalsRegistry.FeatureGateFor(b.componentName)
+}
+
+// featuresEnabled returns whether all features are enabled in the component's feature gate.
+func (b *Builder) featuresEnabled(features []featuregate.Feature) bool {
+	for _, feature := range features {
+		if !b.FeatureGate().Enabled(feature) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateFeatureGates returns an error for every feature of a registered resource that is not
+// registered in the component's feature gate, which would panic when the resource is installed.
+// Feature gates that cannot list their features are not checked.
+func (b *Builder) validateFeatureGates() []error {
+	gate, ok := b.FeatureGate().(featuregate.MutableFeatureGate)
+	if !ok {
+		return nil
+	}
+	known := gate.GetAll()
+	var errs []error
+	for _, rh := range b.resources {
+		for _, feature := range rh.featureGates {
+			if _, ok := known[feature]; !ok {
+				errs = append(errs, fmt.Errorf("resource %s is gated by unknown feature %q, register it with WithFeatureGates", rh.groupResource, feature))
+			}
+		}
+	}
+	return errs
+}
+
 // WithExtraAdmissionInitializers sets custom admission plugin initialization logic.
 func (b *Builder) WithExtraAdmissionInitializers(f ExtraAdmissionInitializers) *Builder {
 	if f == nil {
@@ -116,6 +306,22 @@ func (b *Builder) WithExtraAdmissionInitializers(f ExtraAdmissionInitializers) *
 	return b
 }
 
+// WithAdmission registers typed admission plugins, see ValidatingAdmission and MutatingAdmission. They are
+// always enabled and run in the given order before the admission plugins of the generic apiserver, e.g. webhooks.
+func (b *Builder) WithAdmission(plugins ...AdmissionPlugin) *Builder {
+	b.admissionPlugins = append(b.admissionPlugins, plugins...)
+	return b
+}
+
+// WithGarbageCollector enables the in-process garbage collector, which deletes the dependents of deleted
+// owners by their ownerReferences among the resources registered with With, like the garbage collector of
+// kube-controller-manager. It honours the background, foreground and orphan propagation policies and
+// blockOwnerDeletion, and is started once the server serves requests.
+func (b *Builder) WithGarbageCollector() *Builder {
+	b.garbageCollector = true
+	return b
+}
+
 // WithSharedInformerFactory registers a SharedInformerFactory to be started when the server starts.
 func (b *Builder) WithSharedInformerFactory(f SharedInformerFactory) *Builder {
 	if f == nil {
@@ -125,6 +331,46 @@ func (b *Builder) WithSharedInformerFactory(f SharedInformerFactory) *Builder {
 	return b
 }
 
+// WithPostStartHook registers a hook that is run once the server serves requests, e.g. to warm caches,
+// register with external systems or start background reconcilers. Long running work must be started in
+// a goroutine that stops when the hook context is done. Names must be unique, a failing hook exits the process.
+func (b *Builder) WithPostStartHook(name string, fn PostStartHookFunc) *Builder {
+	if fn == nil {
+		return b
+	}
+	b.postStartHooks = append(b.postStartHooks, namedHook[PostStartHookFunc]{name: name, fn: fn})
+	return b
+}
+
+// WithPreShutdownHook registers a hook that is run when the server starts to shut down, while it still
+// serves requests, e.g. to flush state or deregister from external systems. Names must be unique.
+func (b *Builder) WithPreShutdownHook(name string, fn PreShutdownHookFunc) *Builder {
+	if fn == nil {
+		return b
+	}
+	b.preShutdownHooks = append(b.preShutdownHooks, namedHook[PreShutdownHookFunc]{name: name, fn: fn})
+	return b
+}
+
+// WithHealthzChecks adds checks to the /healthz endpoint. Check names must be unique per endpoint.
+func (b *Builder) WithHealthzChecks(checks ...healthz.HealthChecker) *Builder {
+	b.healthzChecks = append(b.healthzChecks, checks...)
+	return b
+}
+
+// WithLivezChecks adds checks to the /livez endpoint. A failing liveness check usually restarts the server,
+// so it should only fail if the server cannot recover by itself, not if a dependency is unavailable.
+func (b *Builder) WithLivezChecks(checks ...healthz.HealthChecker) *Builder {
+	b.livezChecks = append(b.livezChecks, checks...)
+	return b
+}
+
+// WithReadyzChecks adds checks to the /readyz endpoint, e.g. for dependencies the strategies rely on.
+func (b *Builder) WithReadyzChecks(checks ...healthz.HealthChecker) *Builder {
+	b.readyzChecks = append(b.readyzChecks, checks...)
+	return b
+}
+
 // WithGroupVersions appends the  group versions to configure storage
 // encoding/decoding for the API server. This must be provided by callers
 // so that the storage codec matches the registered types in the scheme.
@@ -133,29 +379,183 @@ func (b *Builder) WithGroupVersions(gvs ...schema.GroupVersion) *Builder {
 	return b
 }
 
+// WithStoragePrefix overrides the etcd key prefix used for all resources of the given API group.
+// By default every group is stored below "<etcd-prefix>/<group>".
+func (b *Builder) WithStoragePrefix(group, prefix string) *Builder {
+	b.storagePrefixes[group] = prefix
+	return b
+}
+
+// WithInMemoryStorage stores all resources in memory instead of etcd, unless another storage backend is
+// selected with --storage-backend. Data is lost on restart, so this is meant for development and tests.
+func (b *Builder) WithInMemoryStorage() *Builder {
+	b.storageBackend = StorageBackendMemory
+	return b
+}
+
+// WithSQLiteStorage stores all resources in the SQLite database file at path instead of etcd, unless
+// another storage backend is selected with --storage-backend. The path can be changed with --sqlite-path.
+// The SQLite backend uses cgo and must be registered by importing go.opendefense.cloud/kit/apiserver/storage/sqlite,
+// otherwise the server fails to start.
+func (b *Builder) WithSQLiteStorage(path string) *Builder {
+	b.storageBackend = StorageBackendSQLite
+	b.sqlitePath = path
+	return b
+}
+
+// WithEmbeddedEtcd starts an etcd server in-process and stores all resources in it, so no external
+// etcd is needed. The options can be changed with the --embedded-etcd-* flags, if opts is nil the
+// defaults of etcd.NewOptions are used. --etcd-servers is ignored in this mode.
+func (b *Builder) WithEmbeddedEtcd(opts *etcd.Options) *Builder {
+	if opts == nil {
+		opts = etcd.NewOptions()
+	}
+	b.embeddedEtcd = opts
+	return b
+}
+
+// WithStandalone runs the server on its own instead of as an aggregated API server of a kube-apiserver.
+// Requests are authenticated with static tokens, client certificates or OIDC and authorized by a policy file,
+// as configured by the options or the corresponding flags. If opts is nil, the defaults of standalone.NewOptions
+// are used. As there is no kube-apiserver, the core informers, priority and fairness and the admission plugins
+// of the generic apiserver are disabled, so it cannot be combined with WithExtraAdmissionInitializers.
+func (b *Builder) WithStandalone(opts *standalone.Options) *Builder {
+	if opts == nil {
+		opts = standalone.NewOptions()
+	}
+	b.standalone = opts
+	return b
+}
+
+// WithEncryptionProviderConfig encrypts resources at rest as configured by the EncryptionConfiguration file at
+// path, like kube-apiserver. The file selects the encrypted resources and their providers, e.g. aesgcm,
+// secretbox or a KMS v2 plugin listening on a unix socket. The path can be changed with --encryption-provider-config.
+func (b *Builder) WithEncryptionProviderConfig(path string) *Builder {
+	b.encryptionProviderConfig = path
+	return b
+}
+
+// groups returns the distinct API groups of the registered group versions in registration order.
+func (b *Builder) groups() []string {
+	groups := []string{}
+	seen := map[string]bool{}
+	for _, gv := range b.groupVersions {
+		if seen[gv.Group] {
+			continue
+		}
+		seen[gv.Group] = true
+		groups = append(groups, gv.Group)
+	}
+	return groups
+}
+
+// storagePrefix returns the etcd key prefix for the given group below the base prefix.
+func (b *Builder) storagePrefix(base, group string) string {
+	if prefix, ok := b.storagePrefixes[group]; ok {
+		return prefix
+	}
+	return path.Join(base, group)
+}
+
+// prioritizedGroupVersions returns the scheme's prioritized versions of every registered group,
+// both per group and concatenated in group registration order.
+func (b *Builder) prioritizedGroupVersions() (map[string][]schema.GroupVersion, []schema.GroupVersion) {
+	groupVersionsByGroup := map[string][]schema.GroupVersion{}
+	orderedGroupVersions := []schema.GroupVersion{}
+	for _, group := range b.groups() {
+		groupVersionsByGroup[group] = b.scheme.PrioritizedVersionsForGroup(group)
+		orderedGroupVersions = append(orderedGroupVersions, groupVersionsByGroup[group]...)
+	}
+	return groupVersionsByGroup, orderedGroupVersions
+}
+
 // Execute builds and runs the API server, returning an exit code suitable for os.Exit().
 // It configures storage, admission, informers, and launches the server with all registered resources.
 func (b *Builder) Execute() int {
-	// Validate that all group versions belong to the same API group.
-	groupName := ""
-	for _, gv := range b.groupVersions {
-		if groupName != "" && groupName != gv.Group {
-			panic("all exposed resources expected to have the same group")
-		}
-		groupName = gv.Group
+	ctx := genericapiserver.SetupSignalContext()
+	cmd := &cobra.Command{
+		Short: "Launch API server",
+		Long:  "Launch API server",
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return b.setComponentGlobals()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			server, err := b.newServer(c.Context())
+			if err != nil {
+				return err
+			}
+			return server.Run(c.Context())
+		},
+	}
+	cmd.SetContext(ctx)
+
+	utilruntime.Must(b.setup(cmd.Flags()))
+
+	return cli.Run(cmd)
+}
+
+// Build parses args as command line flags and returns the configured API server without running it.
+// Unlike Execute, it neither builds a cobra command nor installs a signal handler, so the caller
+// controls the server's lifecycle, e.g. to embed it in a larger binary or to run it in tests.
+// Build must only be called once per Builder.
+func (b *Builder) Build(ctx context.Context, args []string) (*Server, error) {
+	flags := pflag.NewFlagSet(b.componentName, pflag.ContinueOnError)
+	if err := b.setup(flags); err != nil {
+		return nil, err
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := b.setComponentGlobals(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	// Get the ordered group versions to ensure storage encoding matches the registered types.
-	orderedGroupVersions := b.scheme.PrioritizedVersionsForGroup(groupName)
+	return b.newServer(ctx)
+}
+
+// Run builds the API server with the given command line flags and runs it until ctx is done.
+func (b *Builder) Run(ctx context.Context, args []string) error {
+	server, err := b.Build(ctx, args)
+	if err != nil {
+		return err
+	}
+	return server.Run(ctx)
+}
+
+// setup defaults the recommended options, registers the component with the component globals
+// registry and adds all flags to the given flag set.
+func (b *Builder) setup(flags *pflag.FlagSet) error {
+	_, orderedGroupVersions := b.prioritizedGroupVersions()
 
 	// Set up default recommended options if not already configured.
 	if b.recommendedOptions == nil {
 		b.recommendedOptions = genericoptions.NewRecommendedOptions(
-			fmt.Sprintf("/registry/%s", groupName),
+			defaultEtcdPathPrefix,
 			b.codecs.LegacyCodec(orderedGroupVersions...),
 		)
 	}
 	// Configure storage to use the ordered group versions for encoding.
 	b.recommendedOptions.Etcd.StorageConfig.EncodeVersioner = schema.GroupVersions(orderedGroupVersions)
+	// Default the storage backend, it can still be overridden with --storage-backend.
+	if b.storageBackend != "" {
+		b.recommendedOptions.Etcd.StorageConfig.Type = b.storageBackend
+	}
+	if b.encryptionProviderConfig != "" {
+		b.recommendedOptions.Etcd.EncryptionProviderConfigFilepath = b.encryptionProviderConfig
+	}
+	// Standalone servers do not depend on a kube-apiserver.
+	if b.standalone != nil {
+		b.recommendedOptions.Authentication = nil
+		b.recommendedOptions.Authorization = nil
+		b.recommendedOptions.CoreAPI = nil
+		b.recommendedOptions.Admission = nil
+		b.recommendedOptions.Features.EnablePriorityAndFairness = false
+	}
+	// Default the per-resource storage overrides of the resource handlers.
+	b.recommendedOptions.Etcd.EtcdServersOverrides = append(b.recommendedOptions.Etcd.EtcdServersOverrides, b.etcdServersOverrides...)
+	b.recommendedOptions.Etcd.WatchCacheSizes = append(b.recommendedOptions.Etcd.WatchCacheSizes, b.watchCacheSizes...)
 	// Wire up admission initializers if provided.
 	if b.extraAdmissionInitializers != nil {
 		b.recommendedOptions.ExtraAdmissionInitializers = func(c *genericapiserver.RecommendedConfig) ([]admission.PluginInitializer, error) {
@@ -176,151 +576,398 @@ func (b *Builder) Execute() int {
 		b.componentGlobalsRegistry = compatibility.DefaultComponentGlobalsRegistry
 	}
 
-	ctx := genericapiserver.SetupSignalContext()
-	cmd := &cobra.Command{
-		Short: "Launch API server",
-		Long:  "Launch API server",
-		PersistentPreRunE: func(*cobra.Command, []string) error {
-			if b.skipDefaultComponentGlobalsRegistrySet {
-				return nil
-			}
-			return b.componentGlobalsRegistry.Set()
-		},
-		RunE: func(c *cobra.Command, args []string) error {
-			// Validate essential builder configuration early to provide a helpful error
-			if len(orderedGroupVersions) == 0 {
-				return fmt.Errorf("orderedGroupVersions not set on Builder; call WithGroupVersions(...) before Execute")
-			}
-			// Collect and validate all configuration.
-			errors := []error{}
-			errors = append(errors, b.recommendedOptions.Validate()...)
-			errors = append(errors, b.componentGlobalsRegistry.Validate()...)
-			if err := utilerrors.NewAggregate(errors); err != nil {
-				return err
-			}
+	b.recommendedOptions.AddFlags(flags)
+	if f := flags.Lookup("storage-backend"); f != nil {
+		f.Usage = storageBackendUsage()
+	}
+	if b.sqlitePath == "" {
+		b.sqlitePath = b.componentName + ".db"
+	}
+	flags.StringVar(&b.sqlitePath, "sqlite-path", b.sqlitePath, "The SQLite database file used with --storage-backend=sqlite.")
+	if b.embeddedEtcd != nil {
+		b.embeddedEtcd.AddFlags(flags)
+	}
+	if b.standalone != nil {
+		b.standalone.AddFlags(flags)
+	}
 
-			serverConfig := genericapiserver.NewRecommendedConfig(b.codecs)
+	// Register component versions and feature gates with the global registry.
+	// Register the component with the global component registry,
+	// associating it with its effective version and feature gate configuration.
+	// Will skip if the component has been registered, like in the integration test.
+	_, featureGate := b.componentGlobalsRegistry.ComponentGlobalsOrRegister(
+		b.componentName, basecompatibility.NewEffectiveVersionFromString(b.binaryVersion, b.minCompatibilityVersion, b.minCompatibilityVersion),
+		featuregate.NewVersionedFeatureGate(version.MustParse(b.binaryVersion)))
+
+	// Add the versioned feature specifications of the component.
+	// These specifications, together with the effective version, determine if a feature is enabled.
+	if err := featureGate.AddVersioned(b.featureGates); err != nil {
+		return err
+	}
 
-			// Apply custom configuration functions.
-			for _, fn := range b.recommendedConfigFns {
-				fn(serverConfig)
-			}
+	// Register the default kube component if not already present in the global registry.
+	_, _ = b.componentGlobalsRegistry.ComponentGlobalsOrRegister(basecompatibility.DefaultKubeComponent,
+		basecompatibility.NewEffectiveVersionFromString(baseversion.DefaultKubeBinaryVersion, "", ""), utilfeature.DefaultMutableFeatureGate)
 
-			// Set feature gates and versioning.
-			serverConfig.FeatureGate = b.componentGlobalsRegistry.FeatureGateFor(basecompatibility.DefaultKubeComponent)
-			serverConfig.EffectiveVersion = b.componentGlobalsRegistry.EffectiveVersionFor(b.componentName)
+	// Set the emulation version mapping from the component to the kube component.
+	// This ensures that the emulation version of the latter is determined by the emulation version of the former.
+	emulationVersionMapping := b.emulationVersionMapping
+	if emulationVersionMapping == nil {
+		emulationVersionMapping = defaultEmulationVersionMapping(version.MustParse(b.binaryVersion))
+	}
+	if err := b.componentGlobalsRegistry.SetEmulationVersionMapping(b.componentName, basecompatibility.DefaultKubeComponent, emulationVersionMapping); err != nil {
+		return err
+	}
 
-			// Apply recommended options (TLS, etcd, admission, etc.).
-			if err := b.recommendedOptions.ApplyTo(serverConfig); err != nil {
-				return err
-			}
+	b.componentGlobalsRegistry.AddFlags(flags)
+
+	// TODO: add kube version compatibility matrix and feature gates
+
+	return nil
+}
+
+// setComponentGlobals applies the parsed version and feature gate flags to the component globals registry.
+func (b *Builder) setComponentGlobals() error {
+	if b.skipDefaultComponentGlobalsRegistrySet {
+		return nil
+	}
+	return b.componentGlobalsRegistry.Set()
+}
+
+// informerFactories returns the shared informer factories that are started with the server: the one of the
+// core API, which is not set without a kube-apiserver, and the ones registered with the Builder or by the
+// extra admission initializers.
+func (b *Builder) informerFactories(c *genericapiserver.RecommendedConfig) []SharedInformerFactory {
+	var factories []SharedInformerFactory
+	if c.SharedInformerFactory != nil {
+		factories = append(factories, c.SharedInformerFactory)
+	}
+	return append(factories, b.sharedInformerFactories...)
+}
+
+// newServer validates the configuration and creates the API server with all registered API groups installed.
+func (b *Builder) newServer(ctx context.Context) (_ *Server, err error) {
+	groupVersionsByGroup, orderedGroupVersions := b.prioritizedGroupVersions()
+
+	// Validate essential builder configuration early to provide a helpful error
+	if len(orderedGroupVersions) == 0 {
+		return nil, fmt.Errorf("orderedGroupVersions not set on Builder; call WithGroupVersions(...) before Execute")
+	}
+	for group, groupVersions := range groupVersionsByGroup {
+		if len(groupVersions) == 0 {
+			return nil, fmt.Errorf("no versions of group %q registered in scheme", group)
+		}
+	}
+	// Resources of other storage backends are not stored in etcd,
+	// the etcd options then only provide the generic storage settings.
+	options := *b.recommendedOptions
+	newStorageBackend, usesStorageBackend := storageBackend(options.Etcd.StorageConfig.Type)
+	if usesStorageBackend {
+		options.Etcd = nil
+	}
+	// The embedded etcd replaces any external etcd.
+	useEmbeddedEtcd := b.embeddedEtcd != nil && !usesStorageBackend
+	if useEmbeddedEtcd {
+		options.Etcd.StorageConfig.Transport.ServerList = []string{b.embeddedEtcd.ClientURL()}
+	}
 
-			// Create the fully configured API server.
-			completedConfig := serverConfig.Complete()
-			server, err := completedConfig.New(fmt.Sprintf("%s-apiserver", b.componentName), genericapiserver.NewEmptyDelegate())
+	// Collect and validate all configuration.
+	errors := []error{}
+	errors = append(errors, options.Validate()...)
+	errors = append(errors, b.componentGlobalsRegistry.Validate()...)
+	if useEmbeddedEtcd {
+		errors = append(errors, b.embeddedEtcd.Validate()...)
+	}
+	if b.standalone != nil {
+		errors = append(errors, b.standalone.Validate()...)
+		// The admission plugins the initializers are meant for are disabled in standalone mode.
+		if b.extraAdmissionInitializers != nil {
+			errors = append(errors, fmt.Errorf("extra admission initializers are not supported in standalone mode"))
+		}
+	}
+	errors = append(errors, b.validateFeatureGates()...)
+	if options.Etcd != nil && options.Etcd.StorageConfig.Type == StorageBackendSQLite {
+		errors = append(errors, fmt.Errorf("--storage-backend=%s requires importing %s", StorageBackendSQLite, sqlitePackage))
+	}
+	// Register the conversions of multi-version resources and check that they round-trip.
+	for _, rh := range b.multiVersionResources {
+		if err := rh.addConversions(b.scheme, b.codecs); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if err := utilerrors.NewAggregate(errors); err != nil {
+		return nil, err
+	}
+
+	var embeddedEtcd *etcd.Server
+	if useEmbeddedEtcd {
+		if embeddedEtcd, err = etcd.Start(ctx, b.embeddedEtcd); err != nil {
+			return nil, err
+		}
+		defer func() {
 			if err != nil {
-				return err
+				embeddedEtcd.Stop()
 			}
+		}()
+	}
 
-			// Build API groups from registered handlers and install them into the server.
-			apiGroupMap := map[string]*genericapiserver.APIGroupInfo{}
-			for _, fn := range b.apiGroupFns {
-				apiGroupInfo := fn(b.scheme, b.codecs, &completedConfig)
-				groupName := ""
-				for _, gv := range apiGroupInfo.PrioritizedVersions {
-					groupName = gv.Group
-					break
-				}
-				if groupName == "" {
-					return fmt.Errorf("empty group name is not allowed")
-				}
+	serverConfig := genericapiserver.NewRecommendedConfig(b.codecs)
 
-				// Merge resources from multiple handlers for the same group.
-				if apiGroupInfoPrev, ok := apiGroupMap[groupName]; ok {
-					apiGroupInfoPrev.VersionedResourcesStorageMap = mergeVersionedResourcesStorageMap(apiGroupInfoPrev.VersionedResourcesStorageMap, apiGroupInfo.VersionedResourcesStorageMap)
-				} else {
-					apiGroupMap[groupName] = &apiGroupInfo
-				}
+	// Apply custom configuration functions.
+	for _, fn := range b.recommendedConfigFns {
+		fn(serverConfig)
+	}
 
+	// Make the component feature gate available to strategies at request time.
+	buildHandlerChain := serverConfig.BuildHandlerChainFunc
+	serverConfig.BuildHandlerChainFunc = func(apiHandler http.Handler, c *genericapiserver.Config) http.Handler {
+		return buildHandlerChain(withFeatureGate(apiHandler, b.FeatureGate()), c)
+	}
+
+	// Serve connect subresources without request timeout, like watches and the exec and proxy subresources of pods.
+	longRunningFunc := serverConfig.LongRunningFunc
+	serverConfig.LongRunningFunc = func(r *http.Request, requestInfo *request.RequestInfo) bool {
+		if requestInfo.IsResourceRequest && requestInfo.Subresource != "" &&
+			b.longRunningSubResources.Has(longRunningSubResourceKey(schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}, requestInfo.Subresource)) {
+			return true
+		}
+		return longRunningFunc(r, requestInfo)
+	}
+
+	// Set feature gates and versioning.
+	serverConfig.FeatureGate = b.componentGlobalsRegistry.FeatureGateFor(basecompatibility.DefaultKubeComponent)
+	serverConfig.EffectiveVersion = b.componentGlobalsRegistry.EffectiveVersionFor(b.componentName)
+
+	// Apply recommended options (TLS, etcd, admission, etc.).
+	if err := options.ApplyTo(serverConfig); err != nil {
+		return nil, err
+	}
+	if len(b.admissionPlugins) > 0 {
+		serverConfig.AdmissionControl = newAdmissionChain(b.admissionPlugins, serverConfig.AdmissionControl)
+	}
+	if b.standalone != nil {
+		if err := b.standalone.ApplyTo(wait.ContextForChannel(serverConfig.DrainedNotify()),
+			&serverConfig.Authentication, serverConfig.SecureServing, &serverConfig.Authorization); err != nil {
+			return nil, err
+		}
+	}
+
+	// Store resources on the etcd servers of their override, the generic apiserver only adds health checks for them.
+	if !usesStorageBackend && len(options.Etcd.EtcdServersOverrides) > 0 {
+		overrides, err := genericoptions.ParseEtcdServersOverrides(options.Etcd.EtcdServersOverrides)
+		if err != nil {
+			return nil, err
+		}
+		serverConfig.RESTOptionsGetter = newEtcdServersRESTOptionsGetter(serverConfig.RESTOptionsGetter, overrides)
+	}
+
+	// Store resources in the selected storage backend instead of etcd.
+	var storageBackend kitstorage.Backend
+	if usesStorageBackend {
+		if storageBackend, err = newStorageBackend(b); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				_ = storageBackend.Close()
 			}
+		}()
+		if err := applyEncryptionConfig(&serverConfig.Config, b.recommendedOptions.Etcd); err != nil {
+			return nil, err
+		}
+		serverConfig.RESTOptionsGetter, err = b.backendRESTOptionsGetter(storageBackend, serverConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-			// Install all API groups into the server.
-			for _, apiGroupInfo := range apiGroupMap {
-				if err := server.InstallAPIGroup(apiGroupInfo); err != nil {
-					return err
+	// Register the health checks. Failing informers or storage make the server unready, but do not restart it.
+	informerFactories := b.informerFactories(serverConfig)
+	storageHealthCheck := newStorageHealthChecker()
+	addHealthChecksWithoutLivez(&serverConfig.Config,
+		newInformerSyncHealthChecker(fmt.Sprintf("%s-informer-sync", b.componentName), informerFactories),
+		storageHealthCheck)
+	serverConfig.HealthzChecks = append(serverConfig.HealthzChecks, b.healthzChecks...)
+	serverConfig.LivezChecks = append(serverConfig.LivezChecks, b.livezChecks...)
+	serverConfig.ReadyzChecks = append(serverConfig.ReadyzChecks, b.readyzChecks...)
+
+	// Give every group its own storage prefix and encoding versions.
+	if serverConfig.RESTOptionsGetter != nil {
+		groupStorage := map[string]groupStorageConfig{}
+		for group, groupVersions := range groupVersionsByGroup {
+			groupStorage[group] = groupStorageConfig{
+				prefix:          b.storagePrefix(b.recommendedOptions.Etcd.StorageConfig.Prefix, group),
+				codec:           b.codecs.LegacyCodec(groupVersions...),
+				encodeVersioner: schema.GroupVersions(groupVersions),
+				resources:       map[string]resourceStorageConfig{},
+			}
+		}
+		// Multi-version resources are stored in the version of their hub type.
+		for _, rh := range b.multiVersionResources {
+			if cfg, ok := groupStorage[rh.groupResource.Group]; ok {
+				cfg.resources[rh.groupResource.Resource] = resourceStorageConfig{
+					codec:           b.codecs.LegacyCodec(rh.storageVersion),
+					encodeVersioner: rh.storageVersion,
 				}
 			}
+		}
+		serverConfig.RESTOptionsGetter = &groupRESTOptionsGetter{
+			delegate: serverConfig.RESTOptionsGetter,
+			groups:   groupStorage,
+		}
+	}
 
-			// Register post-start hook to start informers once server is ready.
-			server.AddPostStartHookOrDie(fmt.Sprintf("start-%s-server-informers", b.componentName), func(context genericapiserver.PostStartHookContext) error {
-				// Defensive: the SharedInformerFactory may not be set by the recommended options
-				// in all call sites (callers may provide their own factories via WithSharedInformerFactory).
-				// Avoid a nil-pointer panic by checking for nil before starting.
-				if serverConfig.SharedInformerFactory != nil {
-					serverConfig.SharedInformerFactory.Start(context.Done())
-				}
-				for _, sharedInformerFactory := range b.sharedInformerFactories {
-					sharedInformerFactory.Start(context.Done())
-				}
-				return nil
-			})
+	// Create the fully configured API server.
+	completedConfig := serverConfig.Complete()
+	server, err := completedConfig.New(fmt.Sprintf("%s-apiserver", b.componentName), genericapiserver.NewEmptyDelegate())
+	if err != nil {
+		return nil, err
+	}
+
+	// Build API groups from registered handlers and install them into the server.
+	apiGroupMap := map[string]*genericapiserver.APIGroupInfo{}
+	for _, fn := range b.apiGroupFns {
+		apiGroupInfo := fn(b.scheme, b.codecs, &completedConfig)
+		// Skip resources disabled by feature gates.
+		if len(apiGroupInfo.VersionedResourcesStorageMap) == 0 {
+			continue
+		}
+		groupName := ""
+		for _, gv := range apiGroupInfo.PrioritizedVersions {
+			groupName = gv.Group
+			break
+		}
+		if groupName == "" {
+			return nil, fmt.Errorf("empty group name is not allowed")
+		}
+
+		// Merge resources from multiple handlers for the same group.
+		if apiGroupInfoPrev, ok := apiGroupMap[groupName]; ok {
+			apiGroupInfoPrev.VersionedResourcesStorageMap = mergeVersionedResourcesStorageMap(apiGroupInfoPrev.VersionedResourcesStorageMap, apiGroupInfo.VersionedResourcesStorageMap)
+		} else {
+			apiGroupMap[groupName] = &apiGroupInfo
+		}
 
-			return server.PrepareRun().RunWithContext(ctx)
-		},
 	}
-	cmd.SetContext(ctx)
 
-	flags := cmd.Flags()
-	b.recommendedOptions.AddFlags(flags)
+	// Install all API groups into the server.
+	for _, apiGroupInfo := range apiGroupMap {
+		if err := server.InstallAPIGroup(apiGroupInfo); err != nil {
+			return nil, err
+		}
+		storageHealthCheck.addAPIGroup(apiGroupInfo)
+	}
 
-	// Register component versions and feature gates with the global registry.
-	// TODO: expose to builder
-	defaultVersion := "1.2"
-	// Register the "ARC" component with the global component registry,
-	// associating it with its effective version and feature gate configuration.
-	// Will skip if the component has been registered, like in the integration test.
-	_, _ = b.componentGlobalsRegistry.ComponentGlobalsOrRegister(
-		b.componentName, basecompatibility.NewEffectiveVersionFromString(defaultVersion, "", ""),
-		featuregate.NewVersionedFeatureGate(version.MustParse(defaultVersion)))
-
-	// Add versioned feature specifications for the "BanFlunder" feature.
-	// These specifications, together with the effective version, determine if the feature is enabled.
-	// TODO: expose to builder
-	// utilruntime.Must(arcFeatureGate.AddVersioned(map[featuregate.Feature]featuregate.VersionedSpecs{
-	// 	"BanFlunder": {
-	// 		{Version: version.MustParse("1.0"), Default: false, PreRelease: featuregate.Alpha},
-	// 		{Version: version.MustParse("1.1"), Default: true, PreRelease: featuregate.Beta},
-	// 		{Version: version.MustParse("1.2"), Default: true, PreRelease: featuregate.GA, LockToDefault: true},
-	// 	},
-	// }))
+	s := newServer(server)
 
-	// Register the default kube component if not already present in the global registry.
-	_, _ = b.componentGlobalsRegistry.ComponentGlobalsOrRegister(basecompatibility.DefaultKubeComponent,
-		basecompatibility.NewEffectiveVersionFromString(baseversion.DefaultKubeBinaryVersion, "", ""), utilfeature.DefaultMutableFeatureGate)
+	if embeddedEtcd != nil {
+		// Destroy funcs run after the server has shut down, so etcd outlives all requests.
+		server.RegisterDestroyFunc(embeddedEtcd.Stop)
+		s.failed = embeddedEtcd.Err()
+	}
+	if storageBackend != nil {
+		server.RegisterDestroyFunc(func() {
+			_ = storageBackend.Close()
+		})
+	}
+	if interval := b.recommendedOptions.Etcd.StorageConfig.CompactionInterval; storageBackend != nil && interval > 0 {
+		// Register post-start hook to compact the storage backend periodically, etcd is compacted by the generic apiserver.
+		server.AddPostStartHookOrDie("start-storage-compactor", func(context genericapiserver.PostStartHookContext) error {
+			go kitstorage.RunCompactor(context, storageBackend, interval)
+			return nil
+		})
+	}
 
-	// Set the emulation version mapping from the "ARC" component to the kube component.
-	// This ensures that the emulation version of the latter is determined by the emulation version of the former.
+	// Register post-start hook to start informers once server is ready.
+	server.AddPostStartHookOrDie(fmt.Sprintf("start-%s-server-informers", b.componentName), func(context genericapiserver.PostStartHookContext) error {
+		for _, sharedInformerFactory := range informerFactories {
+			sharedInformerFactory.Start(context.Done())
+		}
+		return nil
+	})
+
+	// Register post-start hook to start the garbage collector of the installed resources.
+	if b.garbageCollector {
+		server.AddPostStartHookOrDie(fmt.Sprintf("start-%s-garbage-collector", b.componentName), func(context genericapiserver.PostStartHookContext) error {
+			gc, err := newGarbageCollector(context.LoopbackClientConfig, b.garbageCollectorResources())
+			if err != nil {
+				return err
+			}
+			go gc.Run(context, garbageCollectorWorkers)
+			return nil
+		})
+	}
+
+	// Register post-start hook to signal that the server is serving.
+	server.AddPostStartHookOrDie(fmt.Sprintf("%s-server-ready", b.componentName), func(genericapiserver.PostStartHookContext) error {
+		close(s.ready)
+		return nil
+	})
+
+	// Register the custom lifecycle hooks.
+	for _, hook := range b.postStartHooks {
+		fn := hook.fn
+		if err := server.AddPostStartHook(hook.name, func(context genericapiserver.PostStartHookContext) error {
+			return fn(context, &completedConfig)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, hook := range b.preShutdownHooks {
+		fn := hook.fn
+		if err := server.AddPreShutdownHook(hook.name, func() error {
+			return fn(&completedConfig)
+		}); err != nil {
+			return nil, err
+		}
+	}
 
-	versionToKubeVersion := func(ver *version.Version) *version.Version {
-		if ver.Major() != 1 {
+	return s, nil
+}
+
+// backendRESTOptionsGetter returns a RESTOptionsGetter that stores all resources in backend,
+// configured by the etcd options like the RESTOptionsGetter of the generic apiserver.
+func (b *Builder) backendRESTOptionsGetter(backend kitstorage.Backend, serverConfig *genericapiserver.RecommendedConfig) (generic.RESTOptionsGetter, error) {
+	etcdOptions := b.recommendedOptions.Etcd
+	watchCacheSizes, err := genericoptions.ParseWatchCacheSizes(etcdOptions.WatchCacheSizes)
+	if err != nil {
+		return nil, err
+	}
+	storageConfig := etcdOptions.StorageConfig
+	if storageConfig.StorageObjectCountTracker == nil {
+		storageConfig.StorageObjectCountTracker = serverConfig.StorageObjectCountTracker
+	}
+	return &backendRESTOptionsGetter{
+		delegate:         etcdOptions.CreateRESTOptionsGetter(&genericoptions.SimpleStorageFactory{StorageConfig: storageConfig}, serverConfig.ResourceTransformers),
+		backend:          backend,
+		enableWatchCache: etcdOptions.EnableWatchCache,
+		watchCacheSizes:  watchCacheSizes,
+	}, nil
+}
+
+// defaultEmulationVersionMapping maps component versions to kube versions by their minor offset
+// to the binary version, so the binary version maps to the kube binary version the server is built with.
+// Versions of another major version are not mapped.
+func defaultEmulationVersionMapping(binaryVersion *version.Version) basecompatibility.VersionMapping {
+	return func(ver *version.Version) *version.Version {
+		if ver.Major() != binaryVersion.Major() {
 			return nil
 		}
 		kubeVer := version.MustParse(baseversion.DefaultKubeBinaryVersion)
-		// "1.2" maps to kubeVer
-		offset := int(ver.Minor()) - 2
+		offset := int(ver.Minor()) - int(binaryVersion.Minor())
 		mappedVer := kubeVer.OffsetMinor(offset)
 		if mappedVer.GreaterThan(kubeVer) {
 			return kubeVer
 		}
 		return mappedVer
 	}
-	utilruntime.Must(b.componentGlobalsRegistry.SetEmulationVersionMapping(b.componentName, basecompatibility.DefaultKubeComponent, versionToKubeVersion))
-
-	b.componentGlobalsRegistry.AddFlags(flags)
-
-	// TODO: add kube version compatibility matrix and feature gates
+}
 
-	return cli.Run(cmd)
+// withFeatureGate injects the component feature gate into the context of every request.
+func withFeatureGate(handler http.Handler, gate featuregate.FeatureGate) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handler.ServeHTTP(w, req.WithContext(rest.WithFeatureGate(req.Context(), gate)))
+	})
 }
 
 // mergeVersionedResourcesStorageMap combines two versioned storage maps, allowing multiple
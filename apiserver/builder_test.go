@@ -4,12 +4,35 @@
 package apiserver
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"go.opendefense.cloud/kit/apiserver/etcd"
 	"go.opendefense.cloud/kit/apiserver/rest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/apis/example"
+	examplev1 "k8s.io/apiserver/pkg/apis/example/v1"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	restclient "k8s.io/client-go/rest"
+	basecompatibility "k8s.io/component-base/compatibility"
+	"k8s.io/component-base/featuregate"
+	baseversion "k8s.io/component-base/version"
 )
 
 var _ = Describe("mergeVersionedResourcesStorageMap", func() {
@@ -215,3 +238,272 @@ func (m *mockStorage) DeepCopyObject() runtime.Object {
 func (m *mockStorage) GetObjectKind() schema.ObjectKind {
 	return schema.EmptyObjectKind
 }
+
+var _ = Describe("Builder feature gates", func() {
+	var (
+		b         *Builder
+		installed bool
+		rh        ResourceHandler
+	)
+
+	BeforeEach(func() {
+		installed = false
+		gate := featuregate.NewVersionedFeatureGate(version.MustParse("1.0"))
+		Expect(gate.AddVersioned(map[featuregate.Feature]featuregate.VersionedSpecs{
+			"Enabled":  {{Version: version.MustParse("1.0"), Default: true, PreRelease: featuregate.Beta}},
+			"Disabled": {{Version: version.MustParse("1.0"), Default: false, PreRelease: featuregate.Alpha}},
+		})).To(Succeed())
+		registry := basecompatibility.NewComponentGlobalsRegistry()
+		Expect(registry.Register("test", basecompatibility.NewEffectiveVersionFromString("1.0", "", ""), gate)).To(Succeed())
+
+		b = NewBuilder(runtime.NewScheme()).WithComponentName("test")
+		b.componentGlobalsRegistry = registry
+		rh = ResourceHandler{
+			apiGroupFn: func(*runtime.Scheme, serializer.CodecFactory, *genericapiserver.CompletedConfig) genericapiserver.APIGroupInfo {
+				installed = true
+				return genericapiserver.APIGroupInfo{}
+			},
+		}
+	})
+
+	It("should expose the component feature gate", func() {
+		Expect(b.FeatureGate().Enabled("Enabled")).To(BeTrue())
+		Expect(b.FeatureGate().Enabled("Disabled")).To(BeFalse())
+	})
+
+	It("should install resources whose features are enabled", func() {
+		b.With(rh.WithFeatureGates("Enabled"))
+		Expect(b.apiGroupFns).To(HaveLen(1))
+		b.apiGroupFns[0](b.scheme, b.codecs, nil)
+		Expect(installed).To(BeTrue())
+	})
+
+	It("should skip resources whose features are disabled", func() {
+		b.With(rh.WithFeatureGates("Enabled", "Disabled"))
+		Expect(b.apiGroupFns).To(HaveLen(1))
+		b.apiGroupFns[0](b.scheme, b.codecs, nil)
+		Expect(installed).To(BeFalse())
+	})
+
+	It("should reject resources gated by unknown features", func() {
+		b.With(rh.WithFeatureGates("Enabled", "Typo"))
+		Expect(b.validateFeatureGates()).To(ConsistOf(MatchError(ContainSubstring(`unknown feature "Typo"`))))
+	})
+})
+
+var _ = Describe("defaultEmulationVersionMapping", func() {
+	kubeVer := version.MustParse(baseversion.DefaultKubeBinaryVersion)
+	mapping := defaultEmulationVersionMapping(version.MustParse("2.5"))
+
+	It("should map the binary version to the kube binary version", func() {
+		Expect(mapping(version.MustParse("2.5")).EqualTo(kubeVer)).To(BeTrue())
+	})
+
+	It("should map older minor versions by their offset", func() {
+		Expect(mapping(version.MustParse("2.3")).EqualTo(kubeVer.SubtractMinor(2))).To(BeTrue())
+	})
+
+	It("should not map beyond the kube binary version", func() {
+		Expect(mapping(version.MustParse("2.7")).EqualTo(kubeVer)).To(BeTrue())
+	})
+
+	It("should not map other major versions", func() {
+		Expect(mapping(version.MustParse("1.5"))).To(BeNil())
+	})
+})
+
+// freePort returns a free localhost port.
+func freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+var _ = Describe("Builder embedded etcd", func() {
+	var opts *etcd.Options
+
+	BeforeEach(func() {
+		opts = etcd.NewOptions()
+		opts.DataDir = filepath.Join(GinkgoT().TempDir(), "etcd")
+		opts.ClientPort = freePort()
+		opts.PeerPort = freePort()
+	})
+
+	// serving returns a function that reports whether the embedded etcd accepts connections.
+	serving := func() func() bool {
+		return func() bool {
+			conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", opts.ClientPort))
+			if err != nil {
+				return false
+			}
+			_ = conn.Close()
+			return true
+		}
+	}
+
+	It("should store resources in the embedded etcd and stop it with the server", func() {
+		b := newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)).WithEmbeddedEtcd(opts)
+		server, err := b.Build(context.Background(), []string{"--storage-backend=etcd3", "--etcd-servers=http://etcd.invalid:2379"})
+		Expect(err).ToNot(HaveOccurred())
+		// The embedded etcd replaces --etcd-servers.
+		Expect(b.recommendedOptions.Etcd.StorageConfig.Transport.ServerList).To(Equal([]string{opts.ClientURL()}))
+		Expect(server.Start(context.Background())).To(Succeed())
+		Eventually(server.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+
+		widgets := widgetClient(server)
+		_, err = widgets.Create(context.Background(), newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		got, err := widgets.Get(context.Background(), "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("size", BeEquivalentTo(1))))
+
+		Expect(server.Stop()).To(Succeed())
+		Expect(serving()()).To(BeFalse())
+	})
+
+	It("should stop the embedded etcd if the build fails", func() {
+		b := newTestBuilder().WithEmbeddedEtcd(opts).
+			WithAPIGroupFn(func(*runtime.Scheme, serializer.CodecFactory, *genericapiserver.CompletedConfig) genericapiserver.APIGroupInfo {
+				// API groups without versions fail to install, after the embedded etcd started.
+				return genericapiserver.APIGroupInfo{VersionedResourcesStorageMap: map[string]map[string]rest.Storage{"v1": {}}}
+			})
+		_, err := b.Build(context.Background(), []string{"--storage-backend=etcd3"})
+		Expect(err).To(MatchError(ContainSubstring("empty group name")))
+		Expect(serving()()).To(BeFalse())
+	})
+})
+
+// newTestBuilder returns a Builder of an API server with in-memory storage that serves on a random
+// localhost port without delegated authentication and authorization, so it can run in tests.
+func newTestBuilder() *Builder {
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, examplev1.SchemeGroupVersion)
+	utilruntime.Must(example.AddToScheme(scheme))
+	utilruntime.Must(examplev1.AddToScheme(scheme))
+
+	b := NewBuilder(scheme).
+		WithComponentName("test").
+		WithInMemoryStorage().
+		WithGroupVersions(examplev1.SchemeGroupVersion)
+	b.componentGlobalsRegistry = basecompatibility.NewComponentGlobalsRegistry()
+	b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+	b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	b.recommendedOptions.SecureServing.Listener = listener
+	b.recommendedOptions.Authentication = nil
+	b.recommendedOptions.Authorization = nil
+	b.recommendedOptions.CoreAPI = nil
+	b.recommendedOptions.Admission = nil
+	b.recommendedOptions.Features.EnablePriorityAndFairness = false
+	return b
+}
+
+// startTestServer builds and starts the server of b and waits until it is ready.
+func startTestServer(b *Builder) *Server {
+	server, err := b.Build(context.Background(), nil)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(server.Start(context.Background())).To(Succeed())
+	Eventually(server.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+	return server
+}
+
+var _ = Describe("Builder hooks", func() {
+	It("should run post-start and pre-shutdown hooks with the completed config", func() {
+		postStart := make(chan *restclient.Config, 2)
+		preShutdown := make(chan *genericapiserver.CompletedConfig, 1)
+		server := startTestServer(newTestBuilder().
+			WithPostStartHook("test-post-start", func(ctx genericapiserver.PostStartHookContext, c *genericapiserver.CompletedConfig) error {
+				postStart <- ctx.LoopbackClientConfig
+				postStart <- c.LoopbackClientConfig
+				return nil
+			}).
+			WithPreShutdownHook("test-pre-shutdown", func(c *genericapiserver.CompletedConfig) error {
+				preShutdown <- c
+				return nil
+			}))
+
+		var loopbackClientConfig *restclient.Config
+		Eventually(postStart).WithTimeout(wait.ForeverTestTimeout).Should(Receive(&loopbackClientConfig))
+		Expect(postStart).To(Receive(Equal(loopbackClientConfig)))
+		client, err := restclient.HTTPClientFor(loopbackClientConfig)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Get(loopbackClientConfig.Host + "/version")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(preShutdown).ToNot(Receive())
+
+		Expect(server.Stop()).To(Succeed())
+		Expect(preShutdown).To(Receive(HaveField("LoopbackClientConfig", loopbackClientConfig)))
+	})
+
+	It("should reject hooks with names that are already registered", func() {
+		b := newTestBuilder().WithPostStartHook("start-test-server-informers", func(genericapiserver.PostStartHookContext, *genericapiserver.CompletedConfig) error {
+			return nil
+		})
+		_, err := b.Build(context.Background(), nil)
+		Expect(err).To(MatchError(ContainSubstring("start-test-server-informers")))
+	})
+})
+
+var _ = Describe("Builder standalone mode", func() {
+	var (
+		host   string
+		client *http.Client
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "tokens.csv"), []byte("alice-token,alice,1\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(`rules:
+  - groups: ["system:authenticated"]
+    verbs: ["get"]
+    nonResourceURLs: ["/version"]
+`), 0o600)).To(Succeed())
+
+		b := newTestBuilder().WithStandalone(nil)
+		server, err := b.Build(context.Background(), []string{
+			"--token-auth-file=" + filepath.Join(dir, "tokens.csv"),
+			"--authorization-policy-file=" + filepath.Join(dir, "policy.yaml"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Start(context.Background())).To(Succeed())
+		DeferCleanup(server.Stop)
+		Eventually(server.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+
+		host = server.GenericAPIServer.LoopbackClientConfig.Host
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	})
+
+	get := func(path, token string) int {
+		req, err := http.NewRequest(http.MethodGet, host+path, nil)
+		Expect(err).ToNot(HaveOccurred())
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	It("should authenticate and authorize requests without a kube-apiserver", func() {
+		Expect(get("/healthz", "")).To(Equal(http.StatusOK))
+		Expect(get("/version", "")).To(Equal(http.StatusForbidden))
+		Expect(get("/version", "alice-token")).To(Equal(http.StatusOK))
+		Expect(get("/metrics", "alice-token")).To(Equal(http.StatusForbidden))
+		Expect(get("/version", "invalid")).To(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("Builder standalone validation", func() {
+	It("should reject extra admission initializers", func() {
+		b := newTestBuilder().WithStandalone(nil).WithExtraAdmissionInitializers(func(*genericapiserver.RecommendedConfig) (SharedInformerFactory, []admission.PluginInitializer, error) {
+			return nil, nil, nil
+		})
+		_, err := b.Build(context.Background(), nil)
+		Expect(err).To(MatchError(ContainSubstring("extra admission initializers are not supported in standalone mode")))
+	})
+})
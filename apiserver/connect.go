@@ -0,0 +1,97 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opendefense.cloud/kit/apiserver/resource"
+	"go.opendefense.cloud/kit/apiserver/rest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	registryrest "k8s.io/apiserver/pkg/registry/rest"
+)
+
+// ConnectFunc returns the handler of a request to a connect subresource of obj, e.g. a stream of logs or a
+// proxy to a backend resolved from the object. The handler gets the request with the path below the subresource,
+// e.g. /healthz for .../myresources/foo/proxy/healthz, and the query of the original request.
+type ConnectFunc[E resource.Object] func(ctx context.Context, obj E) (http.Handler, error)
+
+// ConnectSubResource returns a SubResourceFn of a subresource that serves the handler returned by fn for the given
+// HTTP methods, by default GET. Connect subresources are long-running requests without request timeout, so the
+// handler can stream chunked responses by flushing them, or upgrade the connection, e.g. to a websocket.
+// A proxy that supports both is k8s.io/apimachinery/pkg/util/proxy.UpgradeAwareHandler.
+func ConnectSubResource[E resource.Object](fn ConnectFunc[E], methods ...string) SubResourceFn {
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	return func(store *genericregistry.Store) rest.Storage {
+		return &connectStorage[E]{store: store, fn: fn, methods: methods}
+	}
+}
+
+var _ registryrest.Connecter = &connectStorage[resource.Object]{}
+
+// connectStorage is the storage of a subresource created by ConnectSubResource.
+type connectStorage[E resource.Object] struct {
+	store   *genericregistry.Store
+	fn      ConnectFunc[E]
+	methods []string
+}
+
+// New returns an empty parent object.
+func (s *connectStorage[E]) New() runtime.Object {
+	return s.store.New()
+}
+
+// Destroy does nothing, the storage is shared with and destroyed by the parent resource.
+func (s *connectStorage[E]) Destroy() {}
+
+// NewConnectOptions returns no options, the handler reads the query of the request itself.
+// Requests to paths below the subresource are served too.
+func (s *connectStorage[E]) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, true, ""
+}
+
+// ConnectMethods returns the HTTP methods the subresource is served for.
+func (s *connectStorage[E]) ConnectMethods() []string {
+	return s.methods
+}
+
+// Connect returns the handler for the parent object with the given name.
+func (s *connectStorage[E]) Connect(ctx context.Context, name string, _ runtime.Object, _ registryrest.Responder) (http.Handler, error) {
+	obj, err := s.store.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	parent, ok := obj.(E)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	handler, err := s.fn(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	return withSubResourcePath(handler), nil
+}
+
+// withSubResourcePath serves handler with the path of requests below the subresource.
+func withSubResourcePath(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		subpath := "/"
+		// The parts of a subresource request are the resource, the name, the subresource and the path below.
+		if info, ok := request.RequestInfoFrom(req.Context()); ok && len(info.Parts) > 3 {
+			subpath += strings.Join(info.Parts[3:], "/")
+		}
+		r := req.Clone(req.Context())
+		r.URL.Path = subpath
+		r.URL.RawPath = ""
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+)
+
+var _ = Describe("Connect subresources", func() {
+	var (
+		config  *restclient.Config
+		client  *http.Client
+		next    chan struct{}
+		baseURL string
+	)
+
+	get := func(path string) *http.Response {
+		resp, err := client.Get(baseURL + path)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(resp.Body.Close)
+		return resp
+	}
+
+	BeforeEach(func() {
+		next = make(chan struct{})
+		logs := func(ctx context.Context, obj *Widget) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for i := range 2 {
+					_, _ = fmt.Fprintf(w, "%s line %d\n", obj.Name, i)
+					w.(http.Flusher).Flush()
+					<-next
+				}
+			}), nil
+		}
+		proxy := func(ctx context.Context, obj *Widget) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Upgrade") != "echo" {
+					_, _ = fmt.Fprintf(w, "%s %s %s %s", r.Method, obj.Name, r.URL.Path, r.URL.RawQuery)
+					return
+				}
+				conn, rw, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				defer func() { _ = conn.Close() }()
+				_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+				_ = rw.Flush()
+				line, _ := rw.ReadString('\n')
+				_, _ = rw.WriteString(line)
+				_ = rw.Flush()
+			}), nil
+		}
+
+		builder := newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion).
+			WithSubResource("logs", ConnectSubResource(logs)).
+			WithSubResource("proxy", ConnectSubResource(proxy, http.MethodGet, http.MethodPost)))
+		server := startTestServer(builder)
+		Expect(builder.longRunningSubResources.UnsortedList()).To(ConsistOf(
+			"widgets.test.kit.opendefense.cloud/logs", "widgets.test.kit.opendefense.cloud/proxy"))
+		DeferCleanup(server.Stop)
+		_, err := widgetClient(server).Create(context.Background(), newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		config = server.GenericAPIServer.LoopbackClientConfig
+		client, err = restclient.HTTPClientFor(config)
+		Expect(err).ToNot(HaveOccurred())
+		baseURL = config.Host + "/apis/" + testGroupVersion.String() + "/namespaces/default/widgets/"
+	})
+
+	It("should stream chunked responses", func() {
+		resp := get("foo/logs")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		reader := bufio.NewReader(resp.Body)
+
+		line, err := reader.ReadString('\n')
+		Expect(err).ToNot(HaveOccurred())
+		Expect(line).To(Equal("foo line 0\n"))
+		next <- struct{}{}
+		line, err = reader.ReadString('\n')
+		Expect(err).ToNot(HaveOccurred())
+		Expect(line).To(Equal("foo line 1\n"))
+		next <- struct{}{}
+		_, err = reader.ReadString('\n')
+		Expect(err).To(MatchError(io.EOF))
+	})
+
+	It("should serve paths below the subresource with the allowed methods", func() {
+		resp := get("foo/proxy/a/b?c=d")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo("GET foo /a/b c=d"))
+
+		resp, err := client.Post(baseURL+"foo/proxy", "text/plain", nil)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(resp.Body.Close)
+		Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo("POST foo / "))
+
+		req, err := http.NewRequest(http.MethodDelete, baseURL+"foo/proxy", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err = client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(resp.Body.Close)
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should fail for objects that do not exist", func() {
+		Expect(get("bar/proxy").StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("should upgrade connections", func() {
+		u, err := url.Parse(baseURL + "foo/proxy")
+		Expect(err).ToNot(HaveOccurred())
+		tlsConfig, err := restclient.TLSConfigFor(config)
+		Expect(err).ToNot(HaveOccurred())
+		tlsConfig.NextProtos = []string{"http/1.1"}
+		conn, err := tls.Dial("tcp", u.Host, tlsConfig)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(conn.Close)
+		Expect(conn.SetDeadline(time.Now().Add(wait.ForeverTestTimeout))).To(Succeed())
+
+		_, err = fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nAuthorization: Bearer %s\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n",
+			u.Path, u.Host, config.BearerToken)
+		Expect(err).ToNot(HaveOccurred())
+		reader := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(reader, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusSwitchingProtocols))
+
+		_, err = fmt.Fprint(conn, "hello\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reader.ReadString('\n')).To(Equal("hello\n"))
+	})
+})
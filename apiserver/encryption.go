@@ -0,0 +1,76 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+	encryptionconfigcontroller "k8s.io/apiserver/pkg/server/options/encryptionconfig/controller"
+	encryptionconfigmetrics "k8s.io/apiserver/pkg/server/options/encryptionconfig/metrics"
+)
+
+// applyEncryptionConfig loads the --encryption-provider-config into the resource transformers of c.
+// The generic apiserver only loads it together with etcd, so this is used for the other storage backends.
+// It mirrors the generic apiserver, including the automatic reload and the KMS plugin health checks.
+func applyEncryptionConfig(c *genericapiserver.Config, etcdOptions *genericoptions.EtcdOptions) (err error) {
+	if c.ResourceTransformers != nil {
+		return nil
+	}
+	if len(etcdOptions.EncryptionProviderConfigFilepath) == 0 {
+		if etcdOptions.EncryptionProviderConfigAutomaticReload {
+			return fmt.Errorf("--encryption-provider-config-automatic-reload must be set with --encryption-provider-config")
+		}
+		return nil
+	}
+
+	ctxServer := wait.ContextForChannel(c.DrainedNotify())
+	ctxTransformers, closeTransformers := context.WithCancel(ctxServer)
+	defer func() {
+		// Close partially initialized transformers on error.
+		if err != nil {
+			closeTransformers()
+		}
+	}()
+
+	encryptionConfiguration, err := encryptionconfig.LoadEncryptionConfig(ctxTransformers, etcdOptions.EncryptionProviderConfigFilepath, etcdOptions.EncryptionProviderConfigAutomaticReload, c.APIServerID)
+	if err != nil {
+		return err
+	}
+	encryptionconfigmetrics.RecordEncryptionConfigLastConfigInfo(c.APIServerID, encryptionConfiguration.EncryptionFileContentHash)
+
+	if !etcdOptions.EncryptionProviderConfigAutomaticReload {
+		c.ResourceTransformers = encryptionconfig.StaticTransformers(encryptionConfiguration.Transformers)
+		addHealthChecksWithoutLivez(c, encryptionConfiguration.HealthChecks...)
+		return nil
+	}
+
+	// With automatic reload there is always exactly one health check.
+	if len(encryptionConfiguration.HealthChecks) != 1 {
+		return fmt.Errorf("failed to start kms encryption config hot reload controller. only 1 health check should be available when reload is enabled")
+	}
+	// The dynamic transformers take ownership of the transformers and their cancellation.
+	dynamicTransformers := encryptionconfig.NewDynamicTransformers(encryptionConfiguration.Transformers, encryptionConfiguration.HealthChecks[0], closeTransformers, encryptionConfiguration.KMSCloseGracePeriod)
+	err = c.AddPostStartHook("start-encryption-provider-config-automatic-reload", func(genericapiserver.PostStartHookContext) error {
+		controller := encryptionconfigcontroller.NewDynamicEncryptionConfiguration(
+			"encryption-provider-config-automatic-reload-controller",
+			etcdOptions.EncryptionProviderConfigFilepath,
+			dynamicTransformers,
+			encryptionConfiguration.EncryptionFileContentHash,
+			c.APIServerID,
+		)
+		go controller.Run(ctxServer)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add post start hook for kms encryption config hot reload controller: %w", err)
+	}
+	c.ResourceTransformers = dynamicTransformers
+	addHealthChecksWithoutLivez(c, dynamicTransformers)
+	return nil
+}
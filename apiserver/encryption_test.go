@@ -0,0 +1,128 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/storage/memory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/apis/example"
+	examplev1 "k8s.io/apiserver/pkg/apis/example/v1"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+const testEncryptionConfig = `kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+      - pods.example.apiserver.k8s.io
+    providers:
+      - aesgcm:
+          keys:
+            - name: key1
+              secret: c2VjcmV0IGlzIHNlY3VyZQ==
+      - identity: {}
+  - resources:
+      - secrets
+    providers:
+      - secretbox:
+          keys:
+            - name: key1
+              secret: YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXoxMjM0NTY=
+`
+
+var _ = Describe("applyEncryptionConfig", func() {
+	var (
+		scheme       *runtime.Scheme
+		codecs       serializer.CodecFactory
+		config       *genericapiserver.Config
+		etcdOptions  *genericoptions.EtcdOptions
+		transformsTo func(gr schema.GroupResource) string
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		metav1.AddToGroupVersion(scheme, examplev1.SchemeGroupVersion)
+		utilruntime.Must(example.AddToScheme(scheme))
+		utilruntime.Must(examplev1.AddToScheme(scheme))
+		codecs = serializer.NewCodecFactory(scheme)
+		config = genericapiserver.NewConfig(codecs)
+
+		path := filepath.Join(GinkgoT().TempDir(), "encryption.yaml")
+		Expect(os.WriteFile(path, []byte(testEncryptionConfig), 0o600)).To(Succeed())
+		etcdOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil).Etcd
+		etcdOptions.EncryptionProviderConfigFilepath = path
+
+		transformsTo = func(gr schema.GroupResource) string {
+			out, err := config.ResourceTransformers.TransformerForResource(gr).
+				TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key"))
+			Expect(err).ToNot(HaveOccurred())
+			return string(out)
+		}
+	})
+
+	It("should only encrypt the selected resources with their providers", func() {
+		Expect(applyEncryptionConfig(config, etcdOptions)).To(Succeed())
+		Expect(transformsTo(example.Resource("pods"))).To(HavePrefix("k8s:enc:aesgcm:v1:key1:"))
+		Expect(transformsTo(schema.GroupResource{Resource: "secrets"})).To(HavePrefix("k8s:enc:secretbox:v1:key1:"))
+		Expect(transformsTo(schema.GroupResource{Group: "a.example.com", Resource: "foos"})).To(Equal("data"))
+	})
+
+	It("should reload the configuration if automatic reload is enabled", func() {
+		etcdOptions.EncryptionProviderConfigAutomaticReload = true
+		Expect(applyEncryptionConfig(config, etcdOptions)).To(Succeed())
+		Expect(transformsTo(example.Resource("pods"))).To(HavePrefix("k8s:enc:aesgcm:v1:key1:"))
+		Expect(config.PostStartHooks).To(HaveKey("start-encryption-provider-config-automatic-reload"))
+	})
+
+	It("should require a configuration for automatic reload", func() {
+		etcdOptions.EncryptionProviderConfigFilepath = ""
+		etcdOptions.EncryptionProviderConfigAutomaticReload = true
+		Expect(applyEncryptionConfig(config, etcdOptions)).To(MatchError(ContainSubstring("must be set with --encryption-provider-config")))
+	})
+
+	It("should encrypt resources stored in a storage backend", func() {
+		Expect(applyEncryptionConfig(config, etcdOptions)).To(Succeed())
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+		storageConfig := etcdOptions.StorageConfig
+		storageConfig.Codec = codecs.LegacyCodec(examplev1.SchemeGroupVersion)
+		getter := &backendRESTOptionsGetter{
+			delegate: etcdOptions.CreateRESTOptionsGetter(&genericoptions.SimpleStorageFactory{StorageConfig: storageConfig}, config.ResourceTransformers),
+			backend:  backend,
+		}
+
+		opts, err := getter.GetRESTOptions(example.Resource("pods"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		s, destroy, err := opts.Decorator(opts.StorageConfig, opts.ResourcePrefix, nil,
+			func() runtime.Object { return &example.Pod{} },
+			func() runtime.Object { return &example.PodList{} },
+			nil, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(destroy)
+
+		pod := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+		Expect(s.Create(context.Background(), "/pods/default/foo", pod, &example.Pod{}, 0)).To(Succeed())
+		kv, _, err := backend.Get(context.Background(), "/registry/pods/default/foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(kv.Value)).To(HavePrefix("k8s:enc:aesgcm:v1:key1:"))
+
+		out := &example.Pod{}
+		Expect(s.Get(context.Background(), "/pods/default/foo", apistorage.GetOptions{}, out)).To(Succeed())
+		Expect(out.Name).To(Equal("foo"))
+	})
+})
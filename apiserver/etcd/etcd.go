@@ -0,0 +1,149 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package etcd runs an embedded etcd server in-process, so an API server can be shipped as a
+// self-contained binary without an external etcd.
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// Options configures an embedded etcd server.
+type Options struct {
+	// DataDir is the directory etcd persists its data in.
+	DataDir string
+	// ClientPort is the port etcd serves clients on. It only listens on localhost.
+	ClientPort int
+	// PeerPort is the port etcd serves peers on. It only listens on localhost.
+	PeerPort int
+	// SnapshotCount is the number of committed transactions after which a snapshot is written to disk.
+	SnapshotCount uint64
+	// MaxSnapshots is the number of snapshot files to retain, 0 retains all.
+	MaxSnapshots uint
+	// MaxWALs is the number of WAL files to retain, 0 retains all.
+	MaxWALs uint
+	// StartTimeout is how long to wait for etcd to become ready.
+	StartTimeout time.Duration
+}
+
+// NewOptions returns Options with the defaults of etcd.
+func NewOptions() *Options {
+	return &Options{
+		DataDir:       "default.etcd",
+		ClientPort:    2379,
+		PeerPort:      2380,
+		SnapshotCount: 10000,
+		MaxSnapshots:  5,
+		MaxWALs:       5,
+		StartTimeout:  time.Minute,
+	}
+}
+
+// AddFlags adds flags for the options to the given flag set.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.DataDir, "embedded-etcd-data-dir", o.DataDir, "The directory the embedded etcd persists its data in.")
+	fs.IntVar(&o.ClientPort, "embedded-etcd-client-port", o.ClientPort, "The localhost port the embedded etcd serves clients on.")
+	fs.IntVar(&o.PeerPort, "embedded-etcd-peer-port", o.PeerPort, "The localhost port the embedded etcd serves peers on.")
+	fs.Uint64Var(&o.SnapshotCount, "embedded-etcd-snapshot-count", o.SnapshotCount, "The number of committed transactions after which the embedded etcd writes a snapshot to disk.")
+	fs.UintVar(&o.MaxSnapshots, "embedded-etcd-max-snapshots", o.MaxSnapshots, "The number of snapshot files the embedded etcd retains, 0 retains all.")
+	fs.UintVar(&o.MaxWALs, "embedded-etcd-max-wals", o.MaxWALs, "The number of WAL files the embedded etcd retains, 0 retains all.")
+	fs.DurationVar(&o.StartTimeout, "embedded-etcd-start-timeout", o.StartTimeout, "How long to wait for the embedded etcd to become ready.")
+}
+
+// Validate checks the options for errors.
+func (o *Options) Validate() []error {
+	var errs []error
+	if o.DataDir == "" {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-data-dir must not be empty"))
+	}
+	if o.ClientPort < 1 || o.ClientPort > 65535 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-client-port %d must be between 1 and 65535", o.ClientPort))
+	}
+	if o.PeerPort < 1 || o.PeerPort > 65535 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-peer-port %d must be between 1 and 65535", o.PeerPort))
+	}
+	if o.ClientPort == o.PeerPort {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-client-port and --embedded-etcd-peer-port must differ"))
+	}
+	if o.SnapshotCount == 0 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-snapshot-count must be greater than 0"))
+	}
+	if o.StartTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-start-timeout must be greater than 0"))
+	}
+	return errs
+}
+
+// ClientURL returns the URL clients connect to the embedded etcd with.
+func (o *Options) ClientURL() string {
+	u := localURL(o.ClientPort)
+	return u.String()
+}
+
+// config returns the configuration of a single member etcd cluster listening on localhost.
+func (o *Options) config() *embed.Config {
+	cfg := embed.NewConfig()
+	cfg.Dir = o.DataDir
+	cfg.ListenClientUrls = []url.URL{localURL(o.ClientPort)}
+	cfg.AdvertiseClientUrls = cfg.ListenClientUrls
+	cfg.ListenPeerUrls = []url.URL{localURL(o.PeerPort)}
+	cfg.AdvertisePeerUrls = cfg.ListenPeerUrls
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	cfg.SnapshotCount = o.SnapshotCount
+	cfg.MaxSnapFiles = o.MaxSnapshots
+	cfg.MaxWalFiles = o.MaxWALs
+	cfg.LogLevel = "warn"
+	return cfg
+}
+
+func localURL(port int) url.URL {
+	return url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", port)}
+}
+
+// Server is a running embedded etcd server.
+type Server struct {
+	etcd *embed.Etcd
+}
+
+// Start starts an embedded etcd server and waits until it is ready to serve clients,
+// the StartTimeout has passed or ctx is done.
+func Start(ctx context.Context, o *Options) (*Server, error) {
+	e, err := embed.StartEtcd(o.config())
+	if err != nil {
+		return nil, fmt.Errorf("failed to start embedded etcd: %w", err)
+	}
+
+	timeout := time.NewTimer(o.StartTimeout)
+	defer timeout.Stop()
+	select {
+	case <-e.Server.ReadyNotify():
+		return &Server{etcd: e}, nil
+	case err := <-e.Err():
+		e.Close()
+		return nil, fmt.Errorf("embedded etcd failed: %w", err)
+	case <-timeout.C:
+		e.Close()
+		return nil, fmt.Errorf("embedded etcd did not become ready within %s", o.StartTimeout)
+	case <-ctx.Done():
+		e.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// Err returns a channel that receives errors of the running server, e.g. if it fails to serve clients.
+// The channel is closed when the server is stopped.
+func (s *Server) Err() <-chan error {
+	return s.etcd.Err()
+}
+
+// Stop gracefully shuts the server down.
+func (s *Server) Stop() {
+	s.etcd.Close()
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+)
+
+var _ = Describe("Options", func() {
+	It("should be configurable with flags", func() {
+		o := NewOptions()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		o.AddFlags(flags)
+		Expect(flags.Parse([]string{
+			"--embedded-etcd-data-dir=/var/lib/etcd",
+			"--embedded-etcd-client-port=12379",
+			"--embedded-etcd-peer-port=12380",
+			"--embedded-etcd-snapshot-count=500",
+			"--embedded-etcd-max-snapshots=2",
+			"--embedded-etcd-max-wals=0",
+		})).To(Succeed())
+		Expect(o.Validate()).To(BeEmpty())
+		Expect(o.ClientURL()).To(Equal("http://127.0.0.1:12379"))
+
+		cfg := o.config()
+		Expect(cfg.Dir).To(Equal("/var/lib/etcd"))
+		Expect(cfg.ListenClientUrls).To(Equal([]url.URL{{Scheme: "http", Host: "127.0.0.1:12379"}}))
+		Expect(cfg.AdvertiseClientUrls).To(Equal(cfg.ListenClientUrls))
+		Expect(cfg.ListenPeerUrls).To(Equal([]url.URL{{Scheme: "http", Host: "127.0.0.1:12380"}}))
+		Expect(cfg.AdvertisePeerUrls).To(Equal(cfg.ListenPeerUrls))
+		Expect(cfg.InitialCluster).To(Equal(cfg.Name + "=http://127.0.0.1:12380"))
+		Expect(cfg.SnapshotCount).To(BeEquivalentTo(500))
+		Expect(cfg.MaxSnapFiles).To(BeEquivalentTo(2))
+		Expect(cfg.MaxWalFiles).To(BeEquivalentTo(0))
+	})
+
+	It("should reject invalid options", func() {
+		o := NewOptions()
+		o.DataDir = ""
+		o.ClientPort = 70000
+		o.PeerPort = 0
+		o.SnapshotCount = 0
+		o.StartTimeout = 0
+		Expect(o.Validate()).To(HaveLen(5))
+
+		o = NewOptions()
+		o.PeerPort = o.ClientPort
+		Expect(o.Validate()).To(ConsistOf(MatchError(ContainSubstring("must differ"))))
+	})
+})
@@ -0,0 +1,409 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// garbageCollectorWorkers is the number of objects the garbage collector processes concurrently.
+	garbageCollectorWorkers = 5
+	// ownerUIDIndex indexes objects by the UIDs of their owners.
+	ownerUIDIndex = "ownerUID"
+)
+
+// gcResource is a resource whose objects the garbage collector watches.
+type gcResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// gcItem identifies an object the garbage collector processes.
+type gcItem struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// garbageCollector deletes the dependents of deleted owners by their ownerReferences, like the garbage collector
+// of kube-controller-manager does for the resources it discovers. It honours the background, foreground and
+// orphan propagation policies: dependents of owners that are gone are deleted, owners deleted in the foreground
+// are only deleted once their dependents that block owner deletion are gone, and orphaned dependents lose their
+// references to the owner. Owners of other resources than those watched are never considered absent.
+type garbageCollector struct {
+	client    metadata.Interface
+	resources map[schema.GroupKind]gcResource
+	informers map[schema.GroupKind]cache.SharedIndexInformer
+	queue     workqueue.TypedRateLimitingInterface[gcItem]
+}
+
+// newGarbageCollector returns a garbage collector of the objects of resources, which it reads and deletes with
+// the metadata client of config.
+func newGarbageCollector(config *restclient.Config, resources map[schema.GroupKind]gcResource) (*garbageCollector, error) {
+	client, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	gc := &garbageCollector{
+		client:    client,
+		resources: resources,
+		informers: map[schema.GroupKind]cache.SharedIndexInformer{},
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[gcItem](),
+			workqueue.TypedRateLimitingQueueConfig[gcItem]{Name: "garbage_collector"}),
+	}
+	for gk, r := range resources {
+		informer := metadatainformer.NewFilteredMetadataInformer(client, r.gvr, metav1.NamespaceAll, 0, cache.Indexers{
+			ownerUIDIndex: func(obj interface{}) ([]string, error) {
+				var uids []string
+				for _, ref := range obj.(*metav1.PartialObjectMetadata).OwnerReferences {
+					uids = append(uids, string(ref.UID))
+				}
+				return uids, nil
+			},
+		}, nil).Informer()
+		if _, err := informer.AddEventHandler(gc.eventHandler(gk)); err != nil {
+			return nil, err
+		}
+		gc.informers[gk] = informer
+	}
+	return gc, nil
+}
+
+// eventHandler returns the handler of the events of objects of gk, which queues the objects whose state
+// depends on them.
+func (gc *garbageCollector) eventHandler(gk schema.GroupKind) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			gc.queue.Add(gc.item(gk, obj.(*metav1.PartialObjectMetadata)))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			old, obj := oldObj.(*metav1.PartialObjectMetadata), newObj.(*metav1.PartialObjectMetadata)
+			gc.queue.Add(gc.item(gk, obj))
+			// Owners deleted in the foreground wait for references to them to be removed.
+			if !slices.Equal(old.OwnerReferences, obj.OwnerReferences) {
+				gc.queueOwners(old)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			o, ok := obj.(*metav1.PartialObjectMetadata)
+			if !ok {
+				return
+			}
+			gc.queueOwners(o)
+			for _, item := range gc.dependents(o.UID) {
+				gc.queue.Add(item)
+			}
+		},
+	}
+}
+
+// item returns the gcItem of obj of gk.
+func (gc *garbageCollector) item(gk schema.GroupKind, obj *metav1.PartialObjectMetadata) gcItem {
+	return gcItem{groupKind: gk, namespace: obj.Namespace, name: obj.Name}
+}
+
+// queueOwners queues the owners of obj.
+func (gc *garbageCollector) queueOwners(obj *metav1.PartialObjectMetadata) {
+	for _, ref := range obj.OwnerReferences {
+		if item, ok := gc.ownerItem(obj, ref); ok {
+			gc.queue.Add(item)
+		}
+	}
+}
+
+// ownerItem returns the gcItem of the owner of obj referenced by ref, or false if the owner is not of a watched
+// resource or cannot own obj, as cluster-scoped objects cannot be owned by namespaced ones.
+func (gc *garbageCollector) ownerItem(obj *metav1.PartialObjectMetadata, ref metav1.OwnerReference) (gcItem, bool) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return gcItem{}, false
+	}
+	gk := gv.WithKind(ref.Kind).GroupKind()
+	r, ok := gc.resources[gk]
+	if !ok || (r.namespaced && obj.Namespace == "") {
+		return gcItem{}, false
+	}
+	item := gcItem{groupKind: gk, name: ref.Name}
+	if r.namespaced {
+		item.namespace = obj.Namespace
+	}
+	return item, true
+}
+
+// dependents returns the objects with an owner reference to uid.
+func (gc *garbageCollector) dependents(uid types.UID) []gcItem {
+	var items []gcItem
+	for gk, informer := range gc.informers {
+		objs, err := informer.GetIndexer().ByIndex(ownerUIDIndex, string(uid))
+		if err != nil {
+			continue
+		}
+		for _, obj := range objs {
+			items = append(items, gc.item(gk, obj.(*metav1.PartialObjectMetadata)))
+		}
+	}
+	return items
+}
+
+// get returns the object of item from the informer cache, or nil if it does not exist.
+func (gc *garbageCollector) get(item gcItem) (*metav1.PartialObjectMetadata, error) {
+	key := item.name
+	if item.namespace != "" {
+		key = item.namespace + "/" + item.name
+	}
+	obj, exists, err := gc.informers[item.groupKind].GetIndexer().GetByKey(key)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return obj.(*metav1.PartialObjectMetadata), nil
+}
+
+// Run starts the informers and processes objects with workers until ctx is done.
+func (gc *garbageCollector) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer gc.queue.ShutDown()
+
+	synced := make([]cache.InformerSynced, 0, len(gc.informers))
+	for _, informer := range gc.informers {
+		go informer.RunWithContext(ctx)
+		synced = append(synced, informer.HasSynced)
+	}
+	if !cache.WaitForNamedCacheSync("garbage collector", ctx.Done(), synced...) {
+		return
+	}
+	for range workers {
+		go wait.UntilWithContext(ctx, gc.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (gc *garbageCollector) runWorker(ctx context.Context) {
+	for gc.processNextItem(ctx) {
+	}
+}
+
+func (gc *garbageCollector) processNextItem(ctx context.Context) bool {
+	item, shutdown := gc.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer gc.queue.Done(item)
+
+	if err := gc.process(ctx, item); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to garbage collect object", "kind", item.groupKind, "namespace", item.namespace, "name", item.name)
+		gc.queue.AddRateLimited(item)
+		return true
+	}
+	gc.queue.Forget(item)
+	return true
+}
+
+// process collects the object of item: owners being deleted in the foreground or with orphaned dependents are
+// finalized, dependents are deleted if all their owners are gone or being deleted in the foreground.
+func (gc *garbageCollector) process(ctx context.Context, item gcItem) error {
+	obj, err := gc.get(item)
+	if err != nil || obj == nil {
+		return err
+	}
+	if obj.DeletionTimestamp != nil {
+		switch {
+		case slices.Contains(obj.Finalizers, metav1.FinalizerOrphanDependents):
+			return gc.orphanDependents(ctx, item, obj)
+		case slices.Contains(obj.Finalizers, metav1.FinalizerDeleteDependents):
+			return gc.deleteDependents(ctx, item, obj)
+		}
+		return nil
+	}
+	if len(obj.OwnerReferences) == 0 {
+		return nil
+	}
+
+	var solid, dangling, waiting []types.UID
+	for _, ref := range obj.OwnerReferences {
+		owner, absent, err := gc.getOwner(ctx, obj, ref)
+		switch {
+		case err != nil:
+			return err
+		case absent:
+			dangling = append(dangling, ref.UID)
+		case owner != nil && owner.DeletionTimestamp != nil && slices.Contains(owner.Finalizers, metav1.FinalizerDeleteDependents):
+			waiting = append(waiting, ref.UID)
+		default:
+			solid = append(solid, ref.UID)
+		}
+	}
+	switch {
+	case len(solid) > 0 && len(dangling)+len(waiting) == 0:
+		return nil
+	case len(solid) > 0:
+		// The object stays with its remaining owners.
+		return gc.removeOwnerReferences(ctx, item, obj, append(dangling, waiting...))
+	case len(waiting) > 0 && len(gc.dependents(obj.UID)) > 0:
+		// Dependents that block the deletion of the object must be deleted before its owner.
+		return gc.delete(ctx, item, obj, metav1.DeletePropagationForeground)
+	default:
+		return gc.delete(ctx, item, obj, metav1.DeletePropagationBackground)
+	}
+}
+
+// getOwner returns the owner of obj referenced by ref, and true if it is absent. Owners missing from the informer
+// cache are looked up with the client before they are considered absent, the cache may lag behind. Owners of
+// other resources are never absent, and their object is nil.
+func (gc *garbageCollector) getOwner(ctx context.Context, obj *metav1.PartialObjectMetadata, ref metav1.OwnerReference) (*metav1.PartialObjectMetadata, bool, error) {
+	item, ok := gc.ownerItem(obj, ref)
+	if !ok {
+		return nil, false, nil
+	}
+	owner, err := gc.get(item)
+	if err != nil {
+		return nil, false, err
+	}
+	if owner == nil || owner.UID != ref.UID {
+		r := gc.resources[item.groupKind]
+		owner, err = gc.client.Resource(r.gvr).Namespace(item.namespace).Get(ctx, item.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return owner, owner.UID != ref.UID, nil
+}
+
+// deleteDependents queues the dependents of obj, which is deleted in the foreground, and removes the finalizer of
+// obj once no dependent blocks its deletion.
+func (gc *garbageCollector) deleteDependents(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata) error {
+	blocked := false
+	for _, dependent := range gc.dependents(obj.UID) {
+		gc.queue.Add(dependent)
+		d, err := gc.get(dependent)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		for _, ref := range d.OwnerReferences {
+			if ref.UID == obj.UID && ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+				blocked = true
+			}
+		}
+	}
+	if blocked {
+		// The object is queued again when its blocking dependents are deleted.
+		return nil
+	}
+	return gc.removeFinalizer(ctx, item, obj, metav1.FinalizerDeleteDependents)
+}
+
+// orphanDependents removes the references to obj from its dependents and then the finalizer of obj.
+func (gc *garbageCollector) orphanDependents(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata) error {
+	for _, dependent := range gc.dependents(obj.UID) {
+		d, err := gc.get(dependent)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		if err := gc.removeOwnerReferences(ctx, dependent, d, []types.UID{obj.UID}); err != nil {
+			return err
+		}
+	}
+	return gc.removeFinalizer(ctx, item, obj, metav1.FinalizerOrphanDependents)
+}
+
+// delete deletes obj with the given propagation policy, unless it has been replaced by an object with another UID.
+func (gc *garbageCollector) delete(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata, policy metav1.DeletionPropagation) error {
+	err := gc.client.Resource(gc.resources[item.groupKind].gvr).Namespace(item.namespace).Delete(ctx, item.name, metav1.DeleteOptions{
+		Preconditions:     &metav1.Preconditions{UID: &obj.UID},
+		PropagationPolicy: &policy,
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// removeOwnerReferences removes the references to the owners with the given UIDs from obj.
+func (gc *garbageCollector) removeOwnerReferences(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata, uids []types.UID) error {
+	refs := []metav1.OwnerReference{}
+	for _, ref := range obj.OwnerReferences {
+		if !slices.Contains(uids, ref.UID) {
+			refs = append(refs, ref)
+		}
+	}
+	return gc.patchMetadata(ctx, item, obj, map[string]interface{}{"ownerReferences": refs})
+}
+
+// removeFinalizer removes finalizer from obj, which deletes it if it was its last one.
+func (gc *garbageCollector) removeFinalizer(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata, finalizer string) error {
+	finalizers := slices.DeleteFunc(slices.Clone(obj.Finalizers), func(f string) bool { return f == finalizer })
+	return gc.patchMetadata(ctx, item, obj, map[string]interface{}{"finalizers": finalizers})
+}
+
+// patchMetadata patches the metadata of obj with a merge patch, which fails if obj changed in the meantime.
+func (gc *garbageCollector) patchMetadata(ctx context.Context, item gcItem, obj *metav1.PartialObjectMetadata, patch map[string]interface{}) error {
+	patch["uid"] = obj.UID
+	patch["resourceVersion"] = obj.ResourceVersion
+	data, err := json.Marshal(map[string]interface{}{"metadata": patch})
+	if err != nil {
+		return err
+	}
+	_, err = gc.client.Resource(gc.resources[item.groupKind].gvr).Namespace(item.namespace).Patch(ctx, item.name, types.MergePatchType, data, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s: %w", item.groupKind, item.name, err)
+	}
+	return nil
+}
+
+// garbageCollectorResources returns the resources registered with With that are installed, by their kind.
+// They are watched in their storage version.
+func (b *Builder) garbageCollectorResources() map[schema.GroupKind]gcResource {
+	resources := map[schema.GroupKind]gcResource{}
+	for _, rh := range b.resources {
+		if rh.hub == nil || !b.featuresEnabled(rh.featureGates) {
+			continue
+		}
+		gvks, _, err := b.scheme.ObjectKinds(rh.hub)
+		if err != nil {
+			continue
+		}
+		gv := rh.storageVersion
+		if gv.Empty() && len(rh.groupVersions) > 0 {
+			gv = rh.groupVersions[0]
+		}
+		for _, gvk := range gvks {
+			if gvk.Group == rh.groupResource.Group {
+				resources[gvk.GroupKind()] = gcResource{gvr: gv.WithResource(rh.groupResource.Resource), namespaced: rh.hub.NamespaceScoped()}
+			}
+		}
+	}
+	return resources
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+)
+
+// newDependentWidget returns an unstructured widget owned by the given widgets.
+func newDependentWidget(name string, blockOwnerDeletion bool, owners ...*unstructured.Unstructured) *unstructured.Unstructured {
+	obj := newWidget(name, 1)
+	var refs []metav1.OwnerReference
+	for _, owner := range owners {
+		refs = append(refs, metav1.OwnerReference{
+			APIVersion:         owner.GetAPIVersion(),
+			Kind:               owner.GetKind(),
+			Name:               owner.GetName(),
+			UID:                owner.GetUID(),
+			BlockOwnerDeletion: ptr.To(blockOwnerDeletion),
+		})
+	}
+	obj.SetOwnerReferences(refs)
+	return obj
+}
+
+var _ = Describe("Garbage collector", func() {
+	var (
+		ctx     context.Context
+		widgets dynamic.ResourceInterface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server := startTestServer(newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)).WithGarbageCollector())
+		DeferCleanup(server.Stop)
+		widgets = widgetClient(server)
+	})
+
+	// deleted returns a function that reports whether the widget name is deleted.
+	deleted := func(name string) func() bool {
+		return func() bool {
+			_, err := widgets.Get(ctx, name, metav1.GetOptions{})
+			return apierrors.IsNotFound(err)
+		}
+	}
+
+	It("should delete dependents of owners deleted in the background", func() {
+		owner, err := widgets.Create(ctx, newWidget("owner", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Create(ctx, newDependentWidget("dependent", false, owner), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(widgets.Delete(ctx, "owner", metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})).To(Succeed())
+		Eventually(deleted("owner")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+		Eventually(deleted("dependent")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+	})
+
+	It("should keep dependents with remaining owners and remove the references to deleted ones", func() {
+		owner, err := widgets.Create(ctx, newWidget("owner", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		other, err := widgets.Create(ctx, newWidget("other", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Create(ctx, newDependentWidget("dependent", false, owner, other), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(widgets.Delete(ctx, "owner", metav1.DeleteOptions{})).To(Succeed())
+		Eventually(func(g Gomega) {
+			got, err := widgets.Get(ctx, "dependent", metav1.GetOptions{})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.GetOwnerReferences()).To(ConsistOf(HaveField("UID", other.GetUID())))
+		}).WithTimeout(wait.ForeverTestTimeout).Should(Succeed())
+	})
+
+	It("should delete owners in the foreground after their blocking dependents", func() {
+		owner, err := widgets.Create(ctx, newWidget("owner", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		// The cleanup finalizer keeps the dependent until it is removed.
+		obj := newDependentWidget("dependent", true, owner)
+		obj.SetLabels(map[string]string{"cleanup": "true"})
+		_, err = widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(widgets.Delete(ctx, "owner", metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationForeground)})).To(Succeed())
+		var dependent *unstructured.Unstructured
+		Eventually(func(g Gomega) {
+			dependent, err = widgets.Get(ctx, "dependent", metav1.GetOptions{})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(dependent.GetDeletionTimestamp()).ToNot(BeNil())
+		}).WithTimeout(wait.ForeverTestTimeout).Should(Succeed())
+		Consistently(deleted("owner")).Should(BeFalse())
+		got, err := widgets.Get(ctx, "owner", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetFinalizers()).To(ContainElement(metav1.FinalizerDeleteDependents))
+
+		dependent.SetFinalizers(nil)
+		_, err = widgets.Update(ctx, dependent, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(deleted("dependent")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+		Eventually(deleted("owner")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+	})
+
+	It("should orphan dependents of owners deleted with the orphan policy", func() {
+		owner, err := widgets.Create(ctx, newWidget("owner", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Create(ctx, newDependentWidget("dependent", true, owner), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(widgets.Delete(ctx, "owner", metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationOrphan)})).To(Succeed())
+		Eventually(deleted("owner")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+		got, err := widgets.Get(ctx, "dependent", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetOwnerReferences()).To(BeEmpty())
+		Consistently(deleted("dependent")).Should(BeFalse())
+	})
+
+	It("should delete dependents of owners that do not exist", func() {
+		owner := newWidget("owner", 1)
+		owner.SetUID(types.UID("missing"))
+		_, err := widgets.Create(ctx, newDependentWidget("dependent", false, owner), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(deleted("dependent")).WithTimeout(wait.ForeverTestTimeout).Should(BeTrue())
+	})
+
+	It("should keep dependents of owners of other resources", func() {
+		_, err := widgets.Create(ctx, newDependentWidget("dependent", false, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "owner", "uid": "missing"},
+		}}), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Consistently(deleted("dependent")).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Builder garbage collector resources", func() {
+	It("should watch the registered resources by their kind in their storage version", func() {
+		b := newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion))
+		Expect(b.garbageCollectorResources()).To(Equal(map[schema.GroupKind]gcResource{
+			{Group: testGroupVersion.Group, Kind: "Widget"}: {gvr: testGroupVersion.WithResource("widgets"), namespaced: true},
+		}))
+	})
+})
@@ -0,0 +1,118 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.opendefense.cloud/kit/apiserver/rest"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// storageHealthCheckTimeout bounds how long the storage health check waits for each resource.
+const storageHealthCheckTimeout = 2 * time.Second
+
+// addHealthChecksWithoutLivez adds health checks to healthz and readyz only, a failing dependency
+// like a KMS plugin or the storage must not restart the server.
+func addHealthChecksWithoutLivez(c *genericapiserver.Config, healthChecks ...healthz.HealthChecker) {
+	c.HealthzChecks = append(c.HealthzChecks, healthChecks...)
+	c.ReadyzChecks = append(c.ReadyzChecks, healthChecks...)
+}
+
+// cacheSyncWaiter is implemented by the shared informer factories of client-go.
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// informerSyncHealthChecker passes once the informers of all shared informer factories have synced.
+type informerSyncHealthChecker struct {
+	name   string
+	checks []healthz.HealthChecker
+}
+
+// newInformerSyncHealthChecker returns a health checker for the factories that can report their sync status.
+func newInformerSyncHealthChecker(name string, factories []SharedInformerFactory) *informerSyncHealthChecker {
+	c := &informerSyncHealthChecker{name: name}
+	for _, factory := range factories {
+		if waiter, ok := factory.(cacheSyncWaiter); ok {
+			c.checks = append(c.checks, healthz.NewInformerSyncHealthz(waiter))
+		}
+	}
+	return c
+}
+
+// Name implements healthz.HealthChecker.
+func (c *informerSyncHealthChecker) Name() string {
+	return c.name
+}
+
+// Check implements healthz.HealthChecker.
+func (c *informerSyncHealthChecker) Check(req *http.Request) error {
+	errs := []error{}
+	for _, check := range c.checks {
+		if err := check.Check(req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// storageHealthChecker passes if the storage of every registered resource is reachable.
+// The resources are added once the API groups are installed, before the server serves requests.
+type storageHealthChecker struct {
+	storages map[schema.GroupResource]apistorage.Interface
+}
+
+// newStorageHealthChecker returns a storage health checker without resources.
+func newStorageHealthChecker() *storageHealthChecker {
+	return &storageHealthChecker{storages: map[schema.GroupResource]apistorage.Interface{}}
+}
+
+// addAPIGroup adds the resources of the registry stores of the API group.
+func (c *storageHealthChecker) addAPIGroup(apiGroupInfo *genericapiserver.APIGroupInfo) {
+	for _, storages := range apiGroupInfo.VersionedResourcesStorageMap {
+		for _, storage := range storages {
+			if store, ok := rest.RegistryStore(storage); ok && store.Storage.Storage != nil {
+				c.storages[store.DefaultQualifiedResource] = store.Storage.Storage
+			}
+		}
+	}
+}
+
+// Name implements healthz.HealthChecker.
+func (c *storageHealthChecker) Name() string {
+	return "storage"
+}
+
+// Check implements healthz.HealthChecker.
+func (c *storageHealthChecker) Check(req *http.Request) error {
+	resources := make([]schema.GroupResource, 0, len(c.storages))
+	for gr := range c.storages {
+		resources = append(resources, gr)
+	}
+	slices.SortFunc(resources, func(a, b schema.GroupResource) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	errs := []error{}
+	for _, gr := range resources {
+		ctx, cancel := context.WithTimeout(req.Context(), storageHealthCheckTimeout)
+		_, err := c.storages[gr].GetCurrentResourceVersion(ctx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("storage of %s is not reachable: %w", gr, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
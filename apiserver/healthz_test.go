@@ -0,0 +1,157 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/rest"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/apis/example"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+)
+
+// fakeInformerFactory is a SharedInformerFactory whose informers have the given sync status.
+type fakeInformerFactory struct {
+	synced bool
+}
+
+func (f *fakeInformerFactory) Start(<-chan struct{}) {}
+
+func (f *fakeInformerFactory) WaitForCacheSync(<-chan struct{}) map[reflect.Type]bool {
+	return map[reflect.Type]bool{reflect.TypeOf(&example.Pod{}): f.synced}
+}
+
+// startOnlyInformerFactory is a SharedInformerFactory that cannot report its sync status.
+type startOnlyInformerFactory struct{}
+
+func (f *startOnlyInformerFactory) Start(<-chan struct{}) {}
+
+// fakeStorage is a storage.Interface that fails to read the current resource version with err.
+type fakeStorage struct {
+	apistorage.Interface
+	err error
+}
+
+func (s *fakeStorage) GetCurrentResourceVersion(context.Context) (uint64, error) {
+	return 1, s.err
+}
+
+var _ = Describe("informerSyncHealthChecker", func() {
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	It("should pass once the informers of all factories have synced", func() {
+		check := newInformerSyncHealthChecker("test-informer-sync", []SharedInformerFactory{&fakeInformerFactory{synced: true}, &startOnlyInformerFactory{}})
+		Expect(check.Name()).To(Equal("test-informer-sync"))
+		Expect(check.Check(req)).To(Succeed())
+	})
+
+	It("should fail if informers have not synced", func() {
+		check := newInformerSyncHealthChecker("test-informer-sync", []SharedInformerFactory{&fakeInformerFactory{synced: true}, &fakeInformerFactory{}})
+		Expect(check.Check(req)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("Builder informer factories", func() {
+	It("should include the informer factory of the core API if it is set", func() {
+		registered := &fakeInformerFactory{synced: true}
+		b := newTestBuilder().WithSharedInformerFactory(registered)
+		c := genericapiserver.NewRecommendedConfig(b.codecs)
+		Expect(b.informerFactories(c)).To(HaveExactElements(registered))
+
+		c.SharedInformerFactory = informers.NewSharedInformerFactory(fake.NewClientset(), 0)
+		Expect(b.informerFactories(c)).To(HaveExactElements(c.SharedInformerFactory, registered))
+	})
+})
+
+var _ = Describe("storageHealthChecker", func() {
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	apiGroupInfo := func(storages map[string]*fakeStorage) *genericapiserver.APIGroupInfo {
+		info := genericapiserver.NewDefaultAPIGroupInfo(example.GroupName, runtime.NewScheme(), nil, serializer.CodecFactory{})
+		info.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{}
+		for resource, storage := range storages {
+			store := &genericregistry.Store{DefaultQualifiedResource: example.Resource(strings.Split(resource, "/")[0])}
+			store.Storage.Storage = storage
+			info.VersionedResourcesStorageMap["v1"][resource] = store
+		}
+		return &info
+	}
+
+	It("should pass if the storage of every resource is reachable", func() {
+		check := newStorageHealthChecker()
+		check.addAPIGroup(apiGroupInfo(map[string]*fakeStorage{"pods": {}, "pods/status": {}}))
+		Expect(check.storages).To(HaveLen(1))
+		Expect(check.Check(req)).To(Succeed())
+	})
+
+	It("should report the resources whose storage is not reachable", func() {
+		check := newStorageHealthChecker()
+		check.addAPIGroup(apiGroupInfo(map[string]*fakeStorage{"pods": {err: errors.New("connection refused")}}))
+		check.storages[schema.GroupResource{Resource: "secrets"}] = &fakeStorage{}
+		Expect(check.Check(req)).To(MatchError("storage of pods.example.apiserver.k8s.io is not reachable: connection refused"))
+	})
+})
+
+var _ = Describe("Builder health checks", func() {
+	var loopbackClient *http.Client
+	var loopbackHost string
+
+	get := func(path string) int {
+		resp, err := loopbackClient.Get(loopbackHost + path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	BeforeEach(func() {
+		failing := healthz.NamedCheck("failing", func(*http.Request) error { return errors.New("unavailable") })
+		server := startTestServer(newTestBuilder().
+			WithSharedInformerFactory(&fakeInformerFactory{synced: true}).
+			WithHealthzChecks(healthz.NamedCheck("custom-healthz", healthz.PingHealthz.Check)).
+			WithLivezChecks(healthz.NamedCheck("custom-livez", healthz.PingHealthz.Check)).
+			WithReadyzChecks(healthz.NamedCheck("custom-readyz", healthz.PingHealthz.Check), failing))
+		DeferCleanup(server.Stop)
+
+		config := server.GenericAPIServer.LoopbackClientConfig
+		var err error
+		loopbackClient, err = restclient.HTTPClientFor(config)
+		Expect(err).ToNot(HaveOccurred())
+		loopbackHost = config.Host
+	})
+
+	It("should register the custom checks on their endpoints only", func() {
+		Expect(get("/healthz/custom-healthz")).To(Equal(http.StatusOK))
+		Expect(get("/livez/custom-healthz")).To(Equal(http.StatusNotFound))
+		Expect(get("/livez/custom-livez")).To(Equal(http.StatusOK))
+		Expect(get("/readyz/custom-livez")).To(Equal(http.StatusNotFound))
+		Expect(get("/readyz/custom-readyz")).To(Equal(http.StatusOK))
+		Expect(get("/readyz/failing")).To(Equal(http.StatusInternalServerError))
+		Expect(get("/readyz")).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("should check the informers and the storage on healthz and readyz", func() {
+		for _, path := range []string{"/healthz/test-informer-sync", "/healthz/storage", "/readyz/test-informer-sync", "/readyz/storage"} {
+			Expect(get(path)).To(Equal(http.StatusOK), path)
+		}
+		Expect(get("/livez/storage")).To(Equal(http.StatusNotFound))
+		Expect(get("/livez")).To(Equal(http.StatusOK))
+	})
+})
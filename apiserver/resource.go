@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"context"
+	"fmt"
 
 	"go.opendefense.cloud/kit/apiserver/resource"
 	"go.opendefense.cloud/kit/apiserver/rest"
@@ -12,17 +13,117 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	registryrest "k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/apiserver/pkg/server"
+	"k8s.io/component-base/featuregate"
+	openapiutil "k8s.io/kube-openapi/pkg/util"
 )
 
 type ResourceHandler struct {
-	groupVersions []schema.GroupVersion
-	apiGroupFn    APIGroupFn
+	groupVersions  []schema.GroupVersion
+	groupResource  schema.GroupResource
+	apiGroupFn     APIGroupFn
+	featureGates   []featuregate.Feature
+	etcdServers    []string
+	watchCacheSize *int
+	subResources   []subResource
+	// selectableFields are the selectable fields by the OpenAPI definition names of the object and list types.
+	selectableFields map[string][]string
+	// hub is the object passed to Resource, the versions added with WithVersions are converted from and to it.
+	hub            resource.Object
+	versions       []ResourceVersion
+	storageVersion schema.GroupVersion
+}
+
+// subResource is a subresource added to a ResourceHandler with WithSubResource.
+type subResource struct {
+	name string
+	fn   SubResourceFn
+}
+
+// WithFeatureGates returns a copy of the ResourceHandler that is only installed
+// if all of the given features are enabled in the component's feature gate.
+func (rh ResourceHandler) WithFeatureGates(features ...featuregate.Feature) ResourceHandler {
+	rh.featureGates = append(append([]featuregate.Feature{}, rh.featureGates...), features...)
+	return rh
+}
+
+// WithEtcdServers returns a copy of the ResourceHandler that stores the resource on the given etcd servers
+// instead of --etcd-servers, like an entry of --etcd-servers-overrides. Setting the flag replaces the overrides
+// of all resources.
+func (rh ResourceHandler) WithEtcdServers(servers ...string) ResourceHandler {
+	rh.etcdServers = append([]string{}, servers...)
+	return rh
+}
+
+// WithWatchCacheSize returns a copy of the ResourceHandler with the given watch cache size, like an entry
+// of --watch-cache-sizes. A size of 0 disables the watch cache for the resource. As in the generic apiserver,
+// positive sizes only enable the watch cache, which is sized dynamically. Setting the flag replaces the
+// sizes of all resources.
+func (rh ResourceHandler) WithWatchCacheSize(size int) ResourceHandler {
+	rh.watchCacheSize = &size
+	return rh
+}
+
+// WithSubResource returns a copy of the ResourceHandler that serves the subresource <resource>/<name> with
+// the storage returned by fn. Like status, the subresource is authorized as its own resource and listed in
+// discovery and OpenAPI. See FieldSubResource and ActionSubResource for common kinds of subresources.
+func (rh ResourceHandler) WithSubResource(name string, fn SubResourceFn) ResourceHandler {
+	rh.subResources = append(append([]subResource{}, rh.subResources...), subResource{name: name, fn: fn})
+	return rh
+}
+
+// withSubResources wraps fn to add the subresources of the ResourceHandler to the storage of its resource.
+// Subresources served by a rest.Connecter are added to longRunning, as they may stream their responses.
+func (rh ResourceHandler) withSubResources(fn APIGroupFn, longRunning sets.Set[string]) APIGroupFn {
+	if len(rh.subResources) == 0 {
+		return fn
+	}
+	return func(scheme *runtime.Scheme, codecs serializer.CodecFactory, c *server.CompletedConfig) server.APIGroupInfo {
+		apiGroupInfo := fn(scheme, codecs, c)
+		// The versions usually share their storage, so the subresources are only created once per store.
+		subStorage := map[*genericregistry.Store]map[string]rest.Storage{}
+		for _, storage := range apiGroupInfo.VersionedResourcesStorageMap {
+			if storage == nil {
+				continue
+			}
+			store, ok := rest.RegistryStore(storage[rh.groupResource.Resource])
+			if !ok {
+				panic(fmt.Sprintf("resource %s has no store to add subresources to", rh.groupResource))
+			}
+			if _, ok := subStorage[store]; !ok {
+				subStorage[store] = map[string]rest.Storage{}
+				for _, sub := range rh.subResources {
+					subStorage[store][sub.name] = sub.fn(store)
+				}
+			}
+			for name, s := range subStorage[store] {
+				path := rh.groupResource.Resource + "/" + name
+				if existing, ok := storage[path]; ok && existing != s {
+					panic(fmt.Sprintf("subresource %s of %s is already registered", name, rh.groupResource))
+				}
+				storage[path] = s
+				if _, ok := s.(registryrest.Connecter); ok {
+					longRunning.Insert(longRunningSubResourceKey(rh.groupResource, name))
+				}
+			}
+		}
+		return apiGroupInfo
+	}
+}
+
+// longRunningSubResourceKey returns the key of a subresource in the long-running subresources of a Builder.
+func longRunningSubResourceKey(gr schema.GroupResource, subresource string) string {
+	return gr.String() + "/" + subresource
 }
 
 func Resource[E resource.Object, T resource.ObjectWithDeepCopy[E]](obj T, gvs ...schema.GroupVersion) ResourceHandler {
-	return ResourceHandler{
+	rh := ResourceHandler{
 		groupVersions: gvs,
+		groupResource: obj.GetGroupResource(),
+		hub:           obj,
 		apiGroupFn: func(scheme *runtime.Scheme, codecs serializer.CodecFactory, c *server.CompletedConfig) server.APIGroupInfo {
 			gr := obj.GetGroupResource()
 			strategy := rest.NewDefaultStrategy(obj, scheme, gr)
@@ -30,9 +131,13 @@ func Resource[E resource.Object, T resource.ObjectWithDeepCopy[E]](obj T, gvs ..
 			if err != nil {
 				panic(err)
 			}
+			if err := rest.AddFieldLabelConversionFuncs(scheme, obj); err != nil {
+				panic(err)
+			}
+			rest.AddDefaultingFuncs(scheme, obj, obj.NewList())
 
 			storage := map[string]rest.Storage{}
-			storage[gr.Resource] = store
+			storage[gr.Resource] = rest.WithDiscoveryInfo(store, obj)
 
 			if _, ok := any(obj).(resource.ObjectWithStatusSubResource); ok {
 				statusPrepareForUpdate := func(ctx context.Context, obj, old runtime.Object) {
@@ -49,7 +154,12 @@ func Resource[E resource.Object, T resource.ObjectWithDeepCopy[E]](obj T, gvs ..
 					RESTUpdateStrategy: store.UpdateStrategy,
 					OverrideFn:         statusPrepareForUpdate,
 				}
-				storage[gr.Resource+"/status"] = &statusStore
+				storage[gr.Resource+"/status"] = &fieldSubResourceStorage{store: &statusStore}
+			}
+
+			if _, ok := any(obj).(resource.ObjectWithScaleSubResource); ok {
+				addScaleToScheme(scheme)
+				storage[gr.Resource+"/scale"] = &scaleStorage{store: store}
 			}
 
 			apiGroupInfo := server.NewDefaultAPIGroupInfo(gr.Group, scheme, metav1.ParameterCodec, codecs)
@@ -64,4 +174,12 @@ func Resource[E resource.Object, T resource.ObjectWithDeepCopy[E]](obj T, gvs ..
 			return apiGroupInfo
 		},
 	}
+	// Invalid selectable fields are reported when the resource is installed.
+	if names, err := rest.SelectableFieldNames(obj); err == nil && len(names) > 0 {
+		rh.selectableFields = map[string][]string{
+			openapiutil.GetCanonicalTypeName(obj):           names,
+			openapiutil.GetCanonicalTypeName(obj.NewList()): names,
+		}
+	}
+	return rh
 }
@@ -53,3 +53,28 @@ type ObjectWithStatusSubResource interface {
 	// Used to preserve status on updates where only spec changes are allowed.
 	CopyStatusTo(runtime.Object)
 }
+
+// ObjectWithScaleSubResource is implemented by resources that have a scale subresource.
+// It maps the replicas of the object to an autoscaling/v1 Scale, e.g. for kubectl scale and autoscalers.
+type ObjectWithScaleSubResource interface {
+	Object
+
+	// GetScale returns the desired replicas of the spec, and the observed replicas and the label
+	// selector of the replicas of the status.
+	GetScale() (specReplicas, statusReplicas int32, selector string)
+
+	// SetSpecReplicas sets the desired replicas of the spec.
+	SetSpecReplicas(replicas int32)
+}
+
+// Convertible is implemented by the types of the other versions of a multi-version resource, the spokes.
+// They are converted from and to the hub type H, which is the type the resource is registered and stored with.
+type Convertible[H Object] interface {
+	runtime.Object
+
+	// ConvertTo converts the receiver to hub.
+	ConvertTo(hub H) error
+
+	// ConvertFrom converts hub to the receiver.
+	ConvertFrom(hub H) error
+}
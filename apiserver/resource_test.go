@@ -0,0 +1,479 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/rest"
+	"go.opendefense.cloud/kit/apiserver/storage/sqlite"
+	extensionsopenapi "k8s.io/apiextensions-apiserver/pkg/generated/openapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	openapicommon "k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/utils/ptr"
+)
+
+// testGroupVersion is the group version of the Widget test resource.
+var testGroupVersion = schema.GroupVersion{Group: "test.kit.opendefense.cloud", Version: "v1"}
+
+// Widget is a resource with a status and a scale used to test resource registration. The same type is used as
+// internal and external version.
+type Widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WidgetSpec   `json:"spec,omitempty"`
+	Status            WidgetStatus `json:"status,omitempty"`
+}
+
+type WidgetSpec struct {
+	Size     int32  `json:"size,omitempty"`
+	Approved bool   `json:"approved,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+	Color    string `json:"color,omitempty"`
+}
+
+type WidgetStatus struct {
+	Phase    string `json:"phase,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+}
+
+func (w *Widget) DeepCopyInto(out *Widget) {
+	*out = *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+func (w *Widget) DeepCopyObject() runtime.Object {
+	out := &Widget{}
+	w.DeepCopyInto(out)
+	return out
+}
+
+func (w *Widget) GetObjectMeta() *metav1.ObjectMeta { return &w.ObjectMeta }
+func (w *Widget) NamespaceScoped() bool             { return true }
+func (w *Widget) New() runtime.Object               { return &Widget{} }
+func (w *Widget) NewList() runtime.Object           { return &WidgetList{} }
+
+func (w *Widget) GetGroupResource() schema.GroupResource {
+	return testGroupVersion.WithResource("widgets").GroupResource()
+}
+
+func (w *Widget) CopyStatusTo(obj runtime.Object) {
+	obj.(*Widget).Status = w.Status
+}
+
+func (w *Widget) ShortNames() []string { return []string{"wd"} }
+func (w *Widget) Categories() []string { return []string{"all", "test"} }
+
+func (w *Widget) SelectableFields() fields.Set {
+	return fields.Set{"spec.size": strconv.Itoa(int(w.Spec.Size)), "status.phase": w.Status.Phase}
+}
+
+func (w *Widget) Default() {
+	if w.Spec.Color == "" {
+		w.Spec.Color = "blue"
+	}
+}
+
+func (w *Widget) ValidationRules() []rest.ValidationRule {
+	return []rest.ValidationRule{
+		{Rule: "self <= 100", FieldPath: "spec.replicas", Message: "must have at most 100 replicas"},
+		{Rule: "self >= oldSelf", FieldPath: "spec.size", Message: "must not decrease"},
+	}
+}
+
+func (w *Widget) PrepareForDelete(context.Context, *metav1.DeleteOptions) {
+	w.Status.Phase = "Terminating"
+}
+
+// CheckGracefulDelete deletes widgets labeled graceful=true with a default grace period of 30 seconds.
+func (w *Widget) CheckGracefulDelete(_ context.Context, options *metav1.DeleteOptions) bool {
+	if w.Labels["graceful"] != "true" {
+		return false
+	}
+	if options.GracePeriodSeconds == nil {
+		options.GracePeriodSeconds = ptr.To[int64](30)
+	}
+	return true
+}
+
+// DefaultFinalizers returns the cleanup finalizer for widgets labeled cleanup=true.
+func (w *Widget) DefaultFinalizers() []string {
+	if w.Labels["cleanup"] != "true" {
+		return nil
+	}
+	return []string{"test.kit.opendefense.cloud/cleanup"}
+}
+
+func (w *Widget) GetScale() (specReplicas, statusReplicas int32, selector string) {
+	return w.Spec.Replicas, w.Status.Replicas, "widget=" + w.Name
+}
+
+func (w *Widget) SetSpecReplicas(replicas int32) {
+	w.Spec.Replicas = replicas
+}
+
+type WidgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Widget `json:"items"`
+}
+
+func (l *WidgetList) DeepCopyObject() runtime.Object {
+	out := &WidgetList{TypeMeta: l.TypeMeta}
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	out.Items = make([]Widget, len(l.Items))
+	for i := range l.Items {
+		l.Items[i].DeepCopyInto(&out.Items[i])
+	}
+	return out
+}
+
+// WidgetRestart is the request body of the restart action of widgets.
+type WidgetRestart struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+func (r *WidgetRestart) DeepCopyObject() runtime.Object {
+	out := *r
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+// getWidgetOpenAPIDefinitions returns the OpenAPI definitions of the widget types and the meta types they use.
+func getWidgetOpenAPIDefinitions(ref openapicommon.ReferenceCallback) map[string]openapicommon.OpenAPIDefinition {
+	object := func(properties map[string]spec.Schema, dependencies ...string) openapicommon.OpenAPIDefinition {
+		properties["apiVersion"] = *spec.StringProperty()
+		properties["kind"] = *spec.StringProperty()
+		return openapicommon.OpenAPIDefinition{
+			Schema:       spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: properties}},
+			Dependencies: dependencies,
+		}
+	}
+	refSchema := func(name string) spec.Schema {
+		return spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref(name)}}
+	}
+	objectMeta := "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"
+	listMeta := "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"
+	widget := "go.opendefense.cloud/kit/apiserver.Widget"
+
+	defs := extensionsopenapi.GetOpenAPIDefinitions(ref)
+	defs[widget] = object(map[string]spec.Schema{
+		"metadata": refSchema(objectMeta),
+		"spec": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"size":     *spec.Int32Property(),
+			"approved": *spec.BoolProperty(),
+			"replicas": *spec.Int32Property(),
+			"color":    *spec.StringProperty(),
+		}}},
+		"status": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"phase":    *spec.StringProperty(),
+			"replicas": *spec.Int32Property(),
+		}}},
+	}, objectMeta)
+	defs[widget+"List"] = object(map[string]spec.Schema{
+		"metadata": refSchema(listMeta),
+		"items":    *spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref(widget)}}),
+	}, listMeta, widget)
+	defs[widget+"Restart"] = object(map[string]spec.Schema{
+		"metadata": refSchema(objectMeta),
+		"reason":   *spec.StringProperty(),
+	}, objectMeta)
+	defs[widget+"V1alpha1"] = object(map[string]spec.Schema{
+		"metadata": refSchema(objectMeta),
+		"spec": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"dimension": *spec.Int32Property(),
+			"replicas":  *spec.Int32Property(),
+		}}},
+		"status": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"phase":    *spec.StringProperty(),
+			"replicas": *spec.Int32Property(),
+		}}},
+	}, objectMeta)
+	defs[widget+"V1alpha1List"] = object(map[string]spec.Schema{
+		"metadata": refSchema(listMeta),
+		"items":    *spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref(widget + "V1alpha1")}}),
+	}, listMeta, widget+"V1alpha1")
+	defs["k8s.io/api/autoscaling/v1.Scale"] = object(map[string]spec.Schema{
+		"metadata": refSchema(objectMeta),
+		"spec": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"replicas": *spec.Int32Property(),
+		}}},
+		"status": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"replicas": *spec.Int32Property(),
+			"selector": *spec.StringProperty(),
+		}}},
+	}, objectMeta)
+	return defs
+}
+
+// newWidgetTestBuilder returns a test Builder that serves widgets with the given ResourceHandler.
+func newWidgetTestBuilder(rh ResourceHandler) *Builder {
+	b := newTestBuilder()
+	for _, gv := range []schema.GroupVersion{testGroupVersion, {Group: testGroupVersion.Group, Version: runtime.APIVersionInternal}} {
+		b.scheme.AddKnownTypes(gv, &Widget{}, &WidgetList{}, &WidgetRestart{})
+	}
+	metav1.AddToGroupVersion(b.scheme, testGroupVersion)
+	// The options of requests are decoded from the core version, like in the generic apiserver.
+	metav1.AddToGroupVersion(b.scheme, schema.GroupVersion{Version: "v1"})
+	return b.With(rh).WithOpenAPIDefinitions("test", "v1", getWidgetOpenAPIDefinitions)
+}
+
+// widgetClient returns a client for the widgets in the default namespace of server.
+func widgetClient(server *Server) dynamic.ResourceInterface {
+	client, err := dynamic.NewForConfig(server.GenericAPIServer.LoopbackClientConfig)
+	Expect(err).ToNot(HaveOccurred())
+	return client.Resource(testGroupVersion.WithResource("widgets")).Namespace(metav1.NamespaceDefault)
+}
+
+// newWidget returns an unstructured widget with the given name and size.
+func newWidget(name string, size int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(testGroupVersion.String())
+	obj.SetKind("Widget")
+	obj.SetName(name)
+	Expect(unstructured.SetNestedField(obj.Object, size, "spec", "size")).To(Succeed())
+	return obj
+}
+
+var _ = Describe("Resource", func() {
+	var (
+		ctx             context.Context
+		widgets         dynamic.ResourceInterface
+		discoveryClient *discovery.DiscoveryClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server := startTestServer(newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)))
+		DeferCleanup(server.Stop)
+		widgets = widgetClient(server)
+		var err error
+		discoveryClient, err = discovery.NewDiscoveryClientForConfig(server.GenericAPIServer.LoopbackClientConfig)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should only update the status through the status subresource", func() {
+		created, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(unstructured.SetNestedField(created.Object, int64(2), "spec", "size")).To(Succeed())
+		Expect(unstructured.SetNestedField(created.Object, "Running", "status", "phase")).To(Succeed())
+		updated, err := widgets.UpdateStatus(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("size", BeEquivalentTo(1))))
+		Expect(updated.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Running")))
+
+		Expect(unstructured.SetNestedField(updated.Object, int64(3), "spec", "size")).To(Succeed())
+		Expect(unstructured.SetNestedField(updated.Object, "Failed", "status", "phase")).To(Succeed())
+		updated, err = widgets.Update(ctx, updated, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("size", BeEquivalentTo(3))))
+		Expect(updated.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Running")))
+	})
+
+	It("should only get and update the object through the status subresource", func() {
+		_, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "status")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetKind()).To(Equal("Widget"))
+		Expect(widgets.Delete(ctx, "foo", metav1.DeleteOptions{}, "status")).To(Satisfy(apierrors.IsMethodNotSupported))
+		_, err = widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{}, "status")
+		Expect(err).To(Satisfy(apierrors.IsMethodNotSupported))
+	})
+
+	It("should default objects on create and update", func() {
+		created, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("color", "blue")))
+
+		Expect(unstructured.SetNestedField(created.Object, "red", "spec", "color")).To(Succeed())
+		updated, err := widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("color", "red")))
+
+		unstructured.RemoveNestedField(updated.Object, "spec", "color")
+		updated, err = widgets.Update(ctx, updated, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("color", "blue")))
+	})
+
+	It("should default objects read from storage", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "test.db")
+		server := startTestServer(newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)).WithSQLiteStorage(path))
+		_, err := widgetClient(server).Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.Stop()).To(Succeed())
+
+		// Remove the default from storage, like for objects stored before it was introduced.
+		backend, err := sqlite.New(path)
+		Expect(err).ToNot(HaveOccurred())
+		kvs, _, _, err := backend.List(ctx, "/registry/", "", 0, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(kvs).To(HaveLen(1))
+		value := strings.Replace(string(kvs[0].Value), `,"color":"blue"`, "", 1)
+		Expect(value).To(ContainSubstring(`"spec":{"size":1}`))
+		res, err := backend.Put(ctx, kvs[0].Key, []byte(value), kvs[0].ModRevision)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Succeeded).To(BeTrue())
+		Expect(backend.Close()).To(Succeed())
+
+		server = startTestServer(newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)).WithSQLiteStorage(path))
+		DeferCleanup(server.Stop)
+		got, err := widgetClient(server).Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("color", "blue")))
+	})
+
+	It("should validate objects with their validation rules", func() {
+		obj := newWidget("foo", 2)
+		Expect(unstructured.SetNestedField(obj.Object, int64(101), "spec", "replicas")).To(Succeed())
+		_, err := widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).To(Satisfy(apierrors.IsInvalid))
+		Expect(err).To(MatchError(ContainSubstring("spec.replicas: Invalid value: \"integer\": must have at most 100 replicas")))
+
+		created, err := widgets.Create(ctx, newWidget("foo", 2), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(created.Object, int64(1), "spec", "size")).To(Succeed())
+		_, err = widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).To(Satisfy(apierrors.IsInvalid))
+		Expect(err).To(MatchError(ContainSubstring("spec.size: Invalid value: \"integer\": must not decrease")))
+	})
+
+	It("should delete objects gracefully", func() {
+		obj := newWidget("foo", 1)
+		obj.SetLabels(map[string]string{"graceful": "true"})
+		_, err := widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(widgets.Delete(ctx, "foo", metav1.DeleteOptions{})).To(Succeed())
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetDeletionTimestamp()).ToNot(BeNil())
+		Expect(got.GetDeletionGracePeriodSeconds()).To(Equal(ptr.To[int64](30)))
+		Expect(got.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Terminating")))
+
+		Expect(widgets.Delete(ctx, "foo", metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)})).To(Succeed())
+		_, err = widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).To(Satisfy(apierrors.IsNotFound))
+	})
+
+	It("should delete objects once their default finalizers are removed", func() {
+		obj := newWidget("foo", 1)
+		obj.SetLabels(map[string]string{"cleanup": "true"})
+		created, err := widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created.GetFinalizers()).To(ConsistOf("test.kit.opendefense.cloud/cleanup"))
+
+		// The finalizers are added again until the object is deleted.
+		created.SetFinalizers(nil)
+		updated, err := widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.GetFinalizers()).To(ConsistOf("test.kit.opendefense.cloud/cleanup"))
+
+		Expect(widgets.Delete(ctx, "foo", metav1.DeleteOptions{})).To(Succeed())
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetDeletionTimestamp()).ToNot(BeNil())
+		Expect(got.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Terminating")))
+
+		got.SetFinalizers(nil)
+		_, err = widgets.Update(ctx, got, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).To(Satisfy(apierrors.IsNotFound))
+	})
+
+	It("should publish the short names, categories and singular name in discovery", func() {
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(testGroupVersion.String())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.APIResources).To(ContainElement(And(
+			HaveField("Name", "widgets"),
+			HaveField("SingularName", "widget"),
+			HaveField("Kind", "Widget"),
+			HaveField("ShortNames", []string{"wd"}),
+			HaveField("Categories", []string{"all", "test"}))))
+	})
+
+	It("should select objects by their selectable fields", func() {
+		_, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		bar, err := widgets.Create(ctx, newWidget("bar", 2), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(bar.Object, "Running", "status", "phase")).To(Succeed())
+		_, err = widgets.UpdateStatus(ctx, bar, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		names := func(selector string) []string {
+			list, err := widgets.List(ctx, metav1.ListOptions{FieldSelector: selector})
+			Expect(err).ToNot(HaveOccurred())
+			var names []string
+			for _, item := range list.Items {
+				names = append(names, item.GetName())
+			}
+			return names
+		}
+		Expect(names("status.phase=Running")).To(ConsistOf("bar"))
+		Expect(names("spec.size=1")).To(ConsistOf("foo"))
+		Expect(names("spec.size!=1,metadata.name!=foo")).To(ConsistOf("bar"))
+		Expect(names("status.phase=Failed")).To(BeEmpty())
+
+		_, err = widgets.List(ctx, metav1.ListOptions{FieldSelector: "spec.color=blue"})
+		Expect(err).To(Satisfy(apierrors.IsBadRequest))
+	})
+
+	It("should watch objects by their selectable fields", func() {
+		w, err := widgets.Watch(ctx, metav1.ListOptions{FieldSelector: "spec.size=2"})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+
+		_, err = widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Create(ctx, newWidget("bar", 2), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		var event watch.Event
+		Eventually(w.ResultChan()).Should(Receive(&event))
+		Expect(event.Type).To(Equal(watch.Added))
+		Expect(event.Object.(*unstructured.Unstructured).GetName()).To(Equal("bar"))
+	})
+
+	It("should publish the selectable fields in OpenAPI", func() {
+		data, err := discoveryClient.RESTClient().Get().AbsPath("/openapi/v3/apis", testGroupVersion.Group, testGroupVersion.Version).DoRaw(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		var doc struct {
+			Components struct {
+				Schemas map[string]map[string]interface{} `json:"schemas"`
+			} `json:"components"`
+		}
+		Expect(json.Unmarshal(data, &doc)).To(Succeed())
+		selectableFields := []interface{}{
+			map[string]interface{}{"fieldPath": "spec.size"},
+			map[string]interface{}{"fieldPath": "status.phase"},
+		}
+		Expect(doc.Components.Schemas).To(HaveKeyWithValue(HaveSuffix(".Widget"),
+			HaveKeyWithValue("x-kubernetes-selectable-fields", selectableFields)))
+		Expect(doc.Components.Schemas).To(HaveKeyWithValue(HaveSuffix(".WidgetList"),
+			HaveKeyWithValue("x-kubernetes-selectable-fields", selectableFields)))
+	})
+})
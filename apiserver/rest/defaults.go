@@ -0,0 +1,41 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Defaulter can be implemented by objects to set default values of unset fields. Defaults are applied
+// whenever an object is decoded, i.e. to request bodies of every served version and to objects read from
+// storage, so stored objects pick up new defaults, and by DefaultStrategy on create and update.
+// Default must be idempotent.
+type Defaulter interface {
+	// Default sets the default values of unset fields of the object.
+	Default()
+}
+
+// AddDefaultingFuncs registers the Default method of obj as defaulting function of its type in scheme,
+// and a defaulting function for list that defaults its items, so scheme.Default and all codecs of scheme
+// apply them. Objects that do not implement Defaulter are ignored. It replaces defaulting functions
+// registered for the types before, e.g. generated by defaulter-gen, which can be called from Default instead.
+func AddDefaultingFuncs(scheme *runtime.Scheme, obj, list runtime.Object) {
+	if _, ok := obj.(Defaulter); !ok {
+		return
+	}
+	scheme.AddTypeDefaultingFunc(obj, func(o interface{}) {
+		o.(Defaulter).Default()
+	})
+	if list == nil {
+		return
+	}
+	scheme.AddTypeDefaultingFunc(list, func(o interface{}) {
+		// Items are always of the type of obj, so they implement Defaulter.
+		_ = meta.EachListItem(o.(runtime.Object), func(item runtime.Object) error {
+			item.(Defaulter).Default()
+			return nil
+		})
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultsObj implements Defaulter
+type defaultsObj struct {
+	testObj
+	Color string
+}
+
+func (d *defaultsObj) DeepCopyObject() runtime.Object {
+	copy := *d
+	return &copy
+}
+
+func (d *defaultsObj) Default() {
+	if d.Color == "" {
+		d.Color = "blue"
+	}
+}
+
+// defaultsObjList is the list type of defaultsObj
+type defaultsObjList struct {
+	testObjList
+	Items []defaultsObj
+}
+
+func (d *defaultsObjList) DeepCopyObject() runtime.Object {
+	copy := *d
+	return &copy
+}
+
+var _ = Describe("Defaulter", func() {
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		scheme.AddKnownTypes(schema.GroupVersion{Group: "arc", Version: "v1"}, &defaultsObj{}, &defaultsObjList{})
+	})
+
+	It("should register the defaults of objects and their lists in the scheme", func() {
+		AddDefaultingFuncs(scheme, &defaultsObj{}, &defaultsObjList{})
+
+		obj := &defaultsObj{}
+		scheme.Default(obj)
+		Expect(obj.Color).To(Equal("blue"))
+		obj = &defaultsObj{Color: "red"}
+		scheme.Default(obj)
+		Expect(obj.Color).To(Equal("red"))
+
+		list := &defaultsObjList{Items: []defaultsObj{{}, {Color: "red"}}}
+		scheme.Default(list)
+		Expect(list.Items[0].Color).To(Equal("blue"))
+		Expect(list.Items[1].Color).To(Equal("red"))
+	})
+
+	It("should ignore objects without defaults", func() {
+		AddDefaultingFuncs(scheme, &testObj{}, &testObjList{})
+		obj := &testObj{}
+		scheme.Default(obj)
+		Expect(obj).To(Equal(&testObj{}))
+	})
+
+	It("should apply the defaults on create and update", func() {
+		ds := DefaultStrategy{}
+		obj := &defaultsObj{}
+		ds.PrepareForCreate(context.Background(), obj)
+		Expect(obj.Color).To(Equal("blue"))
+		Expect(obj.Flag).To(BeTrue())
+
+		obj = &defaultsObj{}
+		ds.PrepareForUpdate(context.Background(), obj, &defaultsObj{})
+		Expect(obj.Color).To(Equal("blue"))
+		Expect(obj.Flag).To(BeTrue())
+	})
+})
@@ -0,0 +1,36 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+
+	"k8s.io/component-base/featuregate"
+)
+
+// featureGateKey is the context key for the component feature gate.
+type featureGateKey struct{}
+
+// WithFeatureGate returns a copy of ctx carrying the given feature gate.
+// The Builder sets the component feature gate on every API request.
+func WithFeatureGate(ctx context.Context, gate featuregate.FeatureGate) context.Context {
+	return context.WithValue(ctx, featureGateKey{}, gate)
+}
+
+// FeatureGateFrom returns the feature gate carried by ctx, or nil if there is none.
+func FeatureGateFrom(ctx context.Context) featuregate.FeatureGate {
+	gate, _ := ctx.Value(featureGateKey{}).(featuregate.FeatureGate)
+	return gate
+}
+
+// FeatureEnabled returns true if the feature is enabled in the feature gate carried by ctx, or false if
+// ctx does not carry a feature gate. Like featuregate.FeatureGate.Enabled, it panics if the feature is not
+// registered, so features must be added with Builder.WithFeatureGates.
+func FeatureEnabled(ctx context.Context, feature featuregate.Feature) bool {
+	gate := FeatureGateFrom(ctx)
+	if gate == nil {
+		return false
+	}
+	return gate.Enabled(feature)
+}
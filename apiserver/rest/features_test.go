@@ -0,0 +1,34 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/component-base/featuregate"
+)
+
+var _ = Describe("FeatureEnabled", func() {
+	It("should return false without a feature gate in the context", func() {
+		Expect(FeatureGateFrom(context.Background())).To(BeNil())
+		Expect(FeatureEnabled(context.Background(), "Foo")).To(BeFalse())
+	})
+
+	It("should consult the feature gate carried by the context", func() {
+		gate := featuregate.NewFeatureGate()
+		Expect(gate.Add(map[featuregate.Feature]featuregate.FeatureSpec{
+			"Foo": {Default: true, PreRelease: featuregate.Beta},
+			"Bar": {Default: false, PreRelease: featuregate.Alpha},
+		})).To(Succeed())
+
+		ctx := WithFeatureGate(context.Background(), gate)
+		Expect(FeatureGateFrom(ctx)).To(Equal(featuregate.FeatureGate(gate)))
+		Expect(FeatureEnabled(ctx, "Foo")).To(BeTrue())
+		Expect(FeatureEnabled(ctx, "Bar")).To(BeFalse())
+		Expect(func() { FeatureEnabled(ctx, "Unknown") }).To(Panic())
+	})
+})
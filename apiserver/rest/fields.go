@@ -0,0 +1,120 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SelectableFieldsProvider can be implemented by objects to support field selectors on fields besides
+// metadata.name and metadata.namespace, like selectableFields of CustomResourceDefinitions,
+// e.g. kubectl get --field-selector spec.color=blue.
+type SelectableFieldsProvider interface {
+	// SelectableFields returns the values of the selectable fields of the object by their path, e.g. spec.color.
+	// The paths must not depend on the object, it is called on an empty object to get them.
+	SelectableFields() fields.Set
+}
+
+// SelectableFieldNames returns the sorted paths of the fields obj declares selectable with
+// SelectableFieldsProvider, or nil if it declares none. It fails if a path is not a dot-separated
+// path or refers to metadata, whose name and namespace are always selectable.
+func SelectableFieldNames(obj runtime.Object) ([]string, error) {
+	p, ok := obj.(SelectableFieldsProvider)
+	if !ok {
+		return nil, nil
+	}
+	var names []string
+	for name := range p.SelectableFields() {
+		if slices.Contains(strings.Split(name, "."), "") {
+			return nil, fmt.Errorf("invalid selectable field %q: must be a dot-separated path, e.g. spec.color", name)
+		}
+		if strings.HasPrefix(name, "metadata.") {
+			return nil, fmt.Errorf("invalid selectable field %q: metadata fields cannot be selectable", name)
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// FieldLabelConversionFunc returns a runtime.FieldLabelConversionFunc that accepts field selectors on
+// metadata.name, metadata.namespace and the given fields, to be registered for every kind with them.
+func FieldLabelConversionFunc(names []string) runtime.FieldLabelConversionFunc {
+	return func(label, value string) (string, string, error) {
+		if slices.Contains(names, label) {
+			return label, value, nil
+		}
+		return runtime.DefaultMetaV1FieldSelectorConversion(label, value)
+	}
+}
+
+// AddFieldLabelConversionFuncs registers FieldLabelConversionFunc for every kind of obj in scheme and for its
+// kind in the given versions, which are served with other types, so the fields obj declares with
+// SelectableFieldsProvider are accepted in field selectors of requests.
+func AddFieldLabelConversionFuncs(scheme *runtime.Scheme, obj runtime.Object, gvs ...schema.GroupVersion) error {
+	names, err := SelectableFieldNames(obj)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	for _, gv := range gvs {
+		gvks = append(gvks, gv.WithKind(gvks[0].Kind))
+	}
+	for _, gvk := range gvks {
+		if err := scheme.AddFieldLabelConversionFunc(gvk, FieldLabelConversionFunc(names)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectableFieldIndexers returns the watch cache indexes of the given fields, which serve lists
+// with an exact match on one of them. For a single field, trigger functions are returned too, which
+// only notify watches with an exact match on it of changes to matching objects. The watch cache
+// supports a single trigger function only.
+func selectableFieldIndexers(names []string) (storage.IndexerFuncs, *cache.Indexers) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	value := func(obj runtime.Object, name string) (string, bool) {
+		p, ok := obj.(SelectableFieldsProvider)
+		if !ok {
+			return "", false
+		}
+		return p.SelectableFields()[name], true
+	}
+
+	indexers := cache.Indexers{}
+	for _, name := range names {
+		indexers[storage.FieldIndex(name)] = func(obj interface{}) ([]string, error) {
+			o, ok := obj.(runtime.Object)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object of type %T", obj)
+			}
+			v, ok := value(o, name)
+			if !ok {
+				return nil, fmt.Errorf("object of type %T has no selectable fields", obj)
+			}
+			return []string{v}, nil
+		}
+	}
+	if len(names) > 1 {
+		return nil, &indexers
+	}
+	return storage.IndexerFuncs{names[0]: func(obj runtime.Object) string {
+		v, _ := value(obj, names[0])
+		return v
+	}}, &indexers
+}
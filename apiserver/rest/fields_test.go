@@ -0,0 +1,116 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// fieldsObj implements SelectableFieldsProvider
+type fieldsObj struct {
+	testObj
+	fields fields.Set
+}
+
+func (f *fieldsObj) DeepCopyObject() runtime.Object {
+	copy := *f
+	return &copy
+}
+
+func (f *fieldsObj) SelectableFields() fields.Set { return f.fields }
+
+var _ = Describe("SelectableFields", func() {
+	var obj *fieldsObj
+
+	BeforeEach(func() {
+		obj = &fieldsObj{
+			testObj: testObj{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}},
+			fields:  fields.Set{"status.phase": "Running", "spec.color": "blue"},
+		}
+	})
+
+	It("should return the sorted selectable field names", func() {
+		Expect(SelectableFieldNames(obj)).To(Equal([]string{"spec.color", "status.phase"}))
+		Expect(SelectableFieldNames(&testObj{})).To(BeEmpty())
+	})
+
+	It("should reject invalid selectable field names", func() {
+		for _, name := range []string{"", "spec.", ".spec", "spec..color", "metadata.labels"} {
+			obj.fields = fields.Set{name: ""}
+			_, err := SelectableFieldNames(obj)
+			Expect(err).To(HaveOccurred(), name)
+		}
+	})
+
+	It("should add the selectable fields to the attributes", func() {
+		_, set, err := GetAttrs(obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(set).To(Equal(fields.Set{
+			"metadata.name":      "foo",
+			"metadata.namespace": "bar",
+			"spec.color":         "blue",
+			"status.phase":       "Running",
+		}))
+	})
+
+	It("should accept field selectors on the selectable fields", func() {
+		convert := FieldLabelConversionFunc([]string{"spec.color"})
+		label, value, err := convert("spec.color", "blue")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(label).To(Equal("spec.color"))
+		Expect(value).To(Equal("blue"))
+		_, _, err = convert("metadata.name", "foo")
+		Expect(err).ToNot(HaveOccurred())
+		_, _, err = convert("spec.size", "1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should register the field label conversion for every kind of the object", func() {
+		scheme := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "arc", Version: "v1"}
+		scheme.AddKnownTypes(gv, &fieldsObj{})
+		Expect(AddFieldLabelConversionFuncs(scheme, obj)).To(Succeed())
+		_, _, err := scheme.ConvertFieldLabel(gv.WithKind("fieldsObj"), "status.phase", "Running")
+		Expect(err).ToNot(HaveOccurred())
+		_, _, err = scheme.ConvertFieldLabel(gv.WithKind("fieldsObj"), "spec.size", "1")
+		Expect(err).To(HaveOccurred())
+
+		obj.fields = fields.Set{"metadata.labels": ""}
+		Expect(AddFieldLabelConversionFuncs(scheme, obj)).ToNot(Succeed())
+	})
+
+	It("should index the selectable fields", func() {
+		triggerFuncs, indexers := selectableFieldIndexers([]string{"spec.color", "status.phase"})
+		Expect(triggerFuncs).To(BeNil())
+		Expect(*indexers).To(HaveLen(2))
+		Expect((*indexers)[storage.FieldIndex("spec.color")](obj)).To(Equal([]string{"blue"}))
+
+		triggerFuncs, indexers = selectableFieldIndexers([]string{"status.phase"})
+		Expect(triggerFuncs).To(HaveLen(1))
+		Expect(triggerFuncs["status.phase"](obj)).To(Equal("Running"))
+		Expect((*indexers)[storage.FieldIndex("status.phase")](obj)).To(Equal([]string{"Running"}))
+
+		triggerFuncs, indexers = selectableFieldIndexers(nil)
+		Expect(triggerFuncs).To(BeNil())
+		Expect(indexers).To(BeNil())
+	})
+
+	It("should match with the selectable fields as index fields", func() {
+		ds := NewDefaultStrategy(obj, nil, schema.GroupResource{Group: "arc", Resource: "fieldsobjs"})
+		pred := ds.Match(labels.Everything(), fields.OneTermEqualSelector("spec.color", "blue"))
+		Expect(pred.IndexFields).To(Equal([]string{"spec.color", "status.phase"}))
+		Expect(pred.Matches(obj)).To(BeTrue())
+		Expect(pred.MatcherIndex(context.Background())).To(ConsistOf(storage.MatchValue{IndexName: storage.FieldIndex("spec.color"), Value: "blue"}))
+	})
+})
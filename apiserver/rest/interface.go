@@ -69,6 +69,65 @@ type PrepareForUpdater interface {
 	PrepareForUpdate(ctx context.Context, old runtime.Object)
 }
 
+// PrepareForDeleter can be implemented by objects to react to their deletion.
+type PrepareForDeleter interface {
+	// PrepareForDelete is invoked when the deletion of the object is requested and
+	// it is not yet being deleted, e.g. to set a terminating status. Changes are
+	// persisted if the object is not deleted immediately, i.e. if it is deleted
+	// gracefully or has finalizers. It may be invoked more than once per request.
+	PrepareForDelete(ctx context.Context, options *metav1.DeleteOptions)
+}
+
+// GracefulDeleteChecker implements an adapted version of rest.RESTGracefulDeleteStrategy and
+// it can be used by objects to override DefaultStrategy behaviour.
+type GracefulDeleteChecker interface {
+	// CheckGracefulDelete returns true if the object is deleted gracefully, i.e.
+	// it is only marked for deletion with a deletion timestamp in the future, and
+	// sets any default values on the options. If it returns true,
+	// options.GracePeriodSeconds must be set.
+	CheckGracefulDelete(ctx context.Context, options *metav1.DeleteOptions) bool
+}
+
+// DeletionGracePeriodProvider can be implemented by objects to be deleted gracefully,
+// like pods. They are marked for deletion for the grace period, which requests can
+// shorten, and must then be deleted with a grace period of 0, e.g. by a controller
+// once it has cleaned up.
+type DeletionGracePeriodProvider interface {
+	// DeletionGracePeriodSeconds returns the default grace period of deletions.
+	DeletionGracePeriodSeconds() int64
+}
+
+// DefaultFinalizersProvider can be implemented by objects to declare finalizers,
+// which are added on create and on update until the object is deleted. Deletions
+// then only mark the object for deletion, it is deleted once controllers removed
+// all finalizers, e.g. after their cleanup.
+type DefaultFinalizersProvider interface {
+	// DefaultFinalizers returns the qualified names of the finalizers of the object,
+	// e.g. example.com/cleanup.
+	DefaultFinalizers() []string
+}
+
+// ShortNamesProvider can be implemented by objects to declare short names of the resource,
+// which discovery publishes for clients like kubectl, e.g. "po" for pods.
+type ShortNamesProvider interface {
+	// ShortNames returns the short names of the resource.
+	ShortNames() []string
+}
+
+// CategoriesProvider can be implemented by objects to declare the categories of the resource,
+// which discovery publishes for clients like kubectl, e.g. "all" for kubectl get all.
+type CategoriesProvider interface {
+	// Categories returns the categories the resource belongs to.
+	Categories() []string
+}
+
+// SingularNameProvider can be implemented by objects to declare the singular name of the resource,
+// which discovery publishes. It defaults to the lowercase kind.
+type SingularNameProvider interface {
+	// GetSingularName returns the singular name of the resource.
+	GetSingularName() string
+}
+
 // TableConverter implements an adapted version of rest.TableConverter
 // it can be used by objects to override DefaultStrategy behaviour.
 type TableConverter interface {
@@ -0,0 +1,145 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const metricsNamespace = "kit"
+
+// storedObjectsTimeout bounds how long collecting the stored objects waits for each resource.
+const storedObjectsTimeout = 5 * time.Second
+
+var (
+	validationFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "strategy",
+			Name:           "validation_failures_total",
+			Help:           "Number of field errors returned by strategy validation, by resource, verb and field path.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"group", "resource", "verb", "field"},
+	)
+
+	hookDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "strategy",
+			Name:           "hook_duration_seconds",
+			Help:           "Latency of strategy hooks in seconds, by resource, verb and hook.",
+			Buckets:        metrics.ExponentialBuckets(0.0001, 4, 9),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"group", "resource", "verb", "hook"},
+	)
+
+	storedObjectsDesc = metrics.NewDesc(
+		metricsNamespace+"_stored_objects",
+		"Number of stored objects by resource.",
+		[]string{"group", "resource"}, nil, metrics.ALPHA, "",
+	)
+
+	storedObjects = &storedObjectsCollector{storages: map[schema.GroupResource]storage.Interface{}}
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the strategy and storage metrics with the legacy registry, which is served
+// on /metrics by the generic apiserver. It is called by NewDefaultStrategy and NewStore.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(validationFailures, hookDuration)
+		legacyregistry.CustomMustRegister(storedObjects)
+	})
+}
+
+// fieldIndexPattern matches list indices and map keys of field paths.
+var fieldIndexPattern = regexp.MustCompile(`\[[^\]]*\]`)
+
+// recordValidationFailures counts the field errors of a validation by their field path.
+// List indices and map keys are replaced by "[*]" to bound the number of series.
+func recordValidationFailures(ctx context.Context, gr schema.GroupResource, verb string, errs field.ErrorList) {
+	verb = requestVerb(ctx, verb)
+	for _, err := range errs {
+		validationFailures.WithLabelValues(gr.Group, gr.Resource, verb, fieldIndexPattern.ReplaceAllString(err.Field, "[*]")).Inc()
+	}
+}
+
+// observeHook records the latency of a strategy hook that started at start.
+func observeHook(ctx context.Context, gr schema.GroupResource, verb, hook string, start time.Time) {
+	hookDuration.WithLabelValues(gr.Group, gr.Resource, requestVerb(ctx, verb), hook).Observe(time.Since(start).Seconds())
+}
+
+// requestVerb returns the verb of the request in ctx, e.g. to tell patches from updates, or verb outside of requests.
+func requestVerb(ctx context.Context, verb string) string {
+	if info, ok := request.RequestInfoFrom(ctx); ok && info.Verb != "" {
+		return info.Verb
+	}
+	return verb
+}
+
+// storedObjectsCollector reports the number of objects in the storage of every store when scraped.
+type storedObjectsCollector struct {
+	metrics.BaseStableCollector
+
+	mu       sync.Mutex
+	storages map[schema.GroupResource]storage.Interface
+}
+
+var _ metrics.StableCollector = &storedObjectsCollector{}
+
+// add starts reporting the objects of the resource in s.
+func (c *storedObjectsCollector) add(gr schema.GroupResource, s storage.Interface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.storages[gr] = s
+}
+
+// remove stops reporting the objects of the resource, unless it has been added with another storage since.
+func (c *storedObjectsCollector) remove(gr schema.GroupResource, s storage.Interface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.storages[gr] == s {
+		delete(c.storages, gr)
+	}
+}
+
+// DescribeWithStability implements metrics.StableCollector.
+func (c *storedObjectsCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- storedObjectsDesc
+}
+
+// CollectWithStability implements metrics.StableCollector.
+func (c *storedObjectsCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	c.mu.Lock()
+	storages := make(map[schema.GroupResource]storage.Interface, len(c.storages))
+	for gr, s := range c.storages {
+		storages[gr] = s
+	}
+	c.mu.Unlock()
+
+	for gr, s := range storages {
+		ctx, cancel := context.WithTimeout(context.Background(), storedObjectsTimeout)
+		stats, err := s.Stats(ctx)
+		cancel()
+		if err != nil {
+			klog.V(4).InfoS("Failed to count stored objects", "resource", gr, "err", err)
+			continue
+		}
+		ch <- metrics.NewLazyConstMetric(storedObjectsDesc, metrics.GaugeValue, float64(stats.ObjectCount), gr.Group, gr.Resource)
+	}
+}
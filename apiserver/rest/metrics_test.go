@@ -0,0 +1,116 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+// countingStorage is a storage.Interface that stores count objects or fails to count them with err.
+type countingStorage struct {
+	storage.Interface
+	count int64
+	err   error
+}
+
+func (s *countingStorage) Stats(context.Context) (storage.Stats, error) {
+	return storage.Stats{ObjectCount: s.count}, s.err
+}
+
+var _ = Describe("Strategy metrics", func() {
+	var (
+		gr schema.GroupResource
+		ds *DefaultStrategy
+	)
+
+	BeforeEach(func() {
+		// Every test uses its own resource, so the metrics of other tests do not interfere.
+		gr = schema.GroupResource{Group: "arc", Resource: strings.ToLower(strings.ReplaceAll(CurrentSpecReport().LeafNodeText, " ", ""))}
+		ds = NewDefaultStrategy(&testObj{}, nil, gr)
+	})
+
+	failures := func(verb, path string) float64 {
+		value, err := testutil.GetCounterMetricValue(validationFailures.WithLabelValues(gr.Group, gr.Resource, verb, path))
+		Expect(err).ToNot(HaveOccurred())
+		return value
+	}
+
+	hookCount := func(verb, hook string) uint64 {
+		count, err := testutil.GetHistogramMetricCount(hookDuration.WithLabelValues(gr.Group, gr.Resource, verb, hook))
+		Expect(err).ToNot(HaveOccurred())
+		return count
+	}
+
+	It("should count validation failures by field path", func() {
+		Expect(ds.Validate(context.Background(), &testObj{})).To(HaveLen(1))
+		Expect(ds.ValidateUpdate(context.Background(), &testObj{}, &testObj{})).To(HaveLen(1))
+		Expect(failures("create", "spec")).To(BeEquivalentTo(1))
+		Expect(failures("update", "spec")).To(BeEquivalentTo(1))
+	})
+
+	It("should label metrics with the verb of the request", func() {
+		ctx := request.WithRequestInfo(context.Background(), &request.RequestInfo{Verb: "patch"})
+		ds.ValidateUpdate(ctx, &testObj{}, &testObj{})
+		ds.PrepareForUpdate(ctx, &testObj{}, &testObj{})
+		Expect(failures("patch", "spec")).To(BeEquivalentTo(1))
+		Expect(hookCount("patch", "PrepareForUpdate")).To(BeEquivalentTo(1))
+	})
+
+	It("should replace indices and keys of field paths", func() {
+		recordValidationFailures(context.Background(), gr, "create", field.ErrorList{
+			field.Invalid(field.NewPath("spec", "items").Index(1).Child("name"), "", ""),
+			field.Invalid(field.NewPath("spec", "items").Index(2).Child("name"), "", ""),
+			field.Invalid(field.NewPath("metadata", "labels").Key("app"), "", ""),
+		})
+		Expect(failures("create", "spec.items[*].name")).To(BeEquivalentTo(2))
+		Expect(failures("create", "metadata.labels[*]")).To(BeEquivalentTo(1))
+	})
+
+	It("should observe the latency of strategy hooks", func() {
+		ds.PrepareForCreate(context.Background(), &testObj{})
+		ds.Validate(context.Background(), &testObj{})
+		_, err := ds.ConvertToTable(context.Background(), &testObj{}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hookCount("create", "PrepareForCreate")).To(BeEquivalentTo(1))
+		Expect(hookCount("create", "Validate")).To(BeEquivalentTo(1))
+		Expect(hookCount("get", "ConvertToTable")).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("storedObjectsCollector", func() {
+	It("should report the stored objects of every resource that can be counted", func() {
+		c := &storedObjectsCollector{storages: map[schema.GroupResource]storage.Interface{}}
+		c.add(schema.GroupResource{Group: "arc", Resource: "testobjs"}, &countingStorage{count: 3})
+		c.add(schema.GroupResource{Group: "arc", Resource: "failing"}, &countingStorage{err: errors.New("unavailable")})
+		removed := &countingStorage{count: 1}
+		c.add(schema.GroupResource{Group: "arc", Resource: "removed"}, removed)
+		c.remove(schema.GroupResource{Group: "arc", Resource: "removed"}, removed)
+
+		Expect(testutil.CustomCollectAndCompare(c, strings.NewReader(`
+# HELP kit_stored_objects [ALPHA] Number of stored objects by resource.
+# TYPE kit_stored_objects gauge
+kit_stored_objects{group="arc",resource="testobjs"} 3
+`), "kit_stored_objects")).To(Succeed())
+	})
+
+	It("should keep resources that were added with another storage", func() {
+		c := &storedObjectsCollector{storages: map[schema.GroupResource]storage.Interface{}}
+		gr := schema.GroupResource{Group: "arc", Resource: "testobjs"}
+		current := &countingStorage{}
+		c.add(gr, current)
+		c.remove(gr, &countingStorage{})
+		Expect(c.storages).To(HaveKeyWithValue(gr, current))
+	})
+})
@@ -5,6 +5,7 @@ package rest
 
 import (
 	"fmt"
+	"strings"
 
 	"go.opendefense.cloud/kit/apiserver/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,6 +23,7 @@ import (
 type Storage = rest.Storage
 
 // GetAttrs extracts the labels and fields from a runtime.Object for use in storage predicates.
+// The fields are those of SelectableFields and of SelectableFieldsProvider, if implemented.
 // Returns an error if the object does not implement resource.Object (i.e., lacks metadata).
 func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
 	provider, ok := obj.(resource.Object)
@@ -29,7 +31,11 @@ func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
 		return nil, nil, fmt.Errorf("given object of type %T does not have metadata", obj)
 	}
 	om := provider.GetObjectMeta()
-	return om.GetLabels(), SelectableFields(om), nil
+	set := SelectableFields(om)
+	if p, ok := obj.(SelectableFieldsProvider); ok {
+		set = generic.MergeFieldsSets(set, p.SelectableFields())
+	}
+	return om.GetLabels(), set, nil
 }
 
 // SelectableFields returns a set of fields (name, namespace, etc.) for the given ObjectMeta.
@@ -39,7 +45,9 @@ func SelectableFields(obj *metav1.ObjectMeta) fields.Set {
 }
 
 // NewStore constructs a genericregistry.Store for a Kubernetes resource type.
-// It wires up the storage strategies, table conversion, and predicate functions.
+// It wires up the storage strategies, table conversion, and predicate functions,
+// indexes the fields declared with SelectableFieldsProvider,
+// and reports the number of stored objects in the kit_stored_objects metric.
 //
 // Parameters:
 //   - scheme: runtime.Scheme for type registration
@@ -62,17 +70,96 @@ func NewStore(
 		NewListFunc:               list,
 		PredicateFunc:             strategy.Match,
 		DefaultQualifiedResource:  gr,
-		SingularQualifiedResource: gr,
+		SingularQualifiedResource: singularResource(scheme, single(), gr),
 		TableConvertor:            strategy,
 		CreateStrategy:            strategy,
 		UpdateStrategy:            strategy,
 		DeleteStrategy:            strategy,
 	}
 
-	// StoreOptions wires up REST options and attribute extraction for filtering.
-	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: GetAttrs}
+	// StoreOptions wires up REST options and attribute extraction for filtering, with watch cache
+	// indexes for selectable fields.
+	fieldNames, err := SelectableFieldNames(single())
+	if err != nil {
+		return nil, fmt.Errorf("resource %s: %w", gr, err)
+	}
+	triggerFuncs, indexers := selectableFieldIndexers(fieldNames)
+	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: GetAttrs, TriggerFunc: triggerFuncs, Indexers: indexers}
 	if err := store.CompleteWithOptions(options); err != nil {
 		return nil, err
 	}
+
+	RegisterMetrics()
+	if s := store.Storage.Storage; s != nil {
+		storedObjects.add(gr, s)
+		destroy := store.DestroyFunc
+		store.DestroyFunc = func() {
+			storedObjects.remove(gr, s)
+			if destroy != nil {
+				destroy()
+			}
+		}
+	}
 	return store, nil
 }
+
+// singularResource returns gr with the singular name of the resource of obj. It is declared by obj with
+// SingularNameProvider and defaults to the lowercase kind, like for CustomResourceDefinitions.
+func singularResource(scheme *runtime.Scheme, obj runtime.Object, gr schema.GroupResource) schema.GroupResource {
+	if p, ok := obj.(SingularNameProvider); ok {
+		gr.Resource = p.GetSingularName()
+	} else if gvks, _, err := scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+		gr.Resource = strings.ToLower(gvks[0].Kind)
+	}
+	return gr
+}
+
+var (
+	_ rest.ShortNamesProvider = &DiscoveryStore{}
+	_ rest.CategoriesProvider = &DiscoveryStore{}
+)
+
+// DiscoveryStore is a genericregistry.Store that publishes the short names and categories of its
+// resource in discovery.
+type DiscoveryStore struct {
+	*genericregistry.Store
+	shortNames []string
+	categories []string
+}
+
+// WithDiscoveryInfo returns store as DiscoveryStore if obj declares short names or categories with
+// ShortNamesProvider or CategoriesProvider, and store as is otherwise.
+func WithDiscoveryInfo(store *genericregistry.Store, obj runtime.Object) Storage {
+	s := &DiscoveryStore{Store: store}
+	if p, ok := obj.(ShortNamesProvider); ok {
+		s.shortNames = p.ShortNames()
+	}
+	if p, ok := obj.(CategoriesProvider); ok {
+		s.categories = p.Categories()
+	}
+	if len(s.shortNames) == 0 && len(s.categories) == 0 {
+		return store
+	}
+	return s
+}
+
+// ShortNames implements rest.ShortNamesProvider.
+func (s *DiscoveryStore) ShortNames() []string {
+	return s.shortNames
+}
+
+// Categories implements rest.CategoriesProvider.
+func (s *DiscoveryStore) Categories() []string {
+	return s.categories
+}
+
+// RegistryStore returns the genericregistry.Store of storage if it is one, or a DiscoveryStore.
+func RegistryStore(storage Storage) (*genericregistry.Store, bool) {
+	switch s := storage.(type) {
+	case *genericregistry.Store:
+		return s, true
+	case *DiscoveryStore:
+		return s.Store, true
+	}
+	return nil, false
+}
@@ -8,8 +8,20 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 )
 
+// discoveryTestObj declares short names, categories and a singular name.
+type discoveryTestObj struct {
+	testObj
+}
+
+func (t *discoveryTestObj) ShortNames() []string    { return []string{"to"} }
+func (t *discoveryTestObj) Categories() []string    { return []string{"all"} }
+func (t *discoveryTestObj) GetSingularName() string { return "testobject" }
+
 var _ = Describe("GetAttrs and SelectableFields", func() {
 	It("should extract labels and fields from a resource.Object", func() {
 		obj := &testObj{}
@@ -30,3 +42,41 @@ var _ = Describe("GetAttrs and SelectableFields", func() {
 		Expect(fieldsSet).To(HaveKeyWithValue("metadata.namespace", "ns"))
 	})
 })
+
+var _ = Describe("Discovery information", func() {
+	gr := schema.GroupResource{Group: "arc", Resource: "testobjs"}
+
+	It("should take the singular name from the object or its kind", func() {
+		scheme := runtime.NewScheme()
+		scheme.AddKnownTypes(schema.GroupVersion{Group: "arc", Version: "v1"}, &testObj{})
+		Expect(singularResource(scheme, &testObj{}, gr)).To(Equal(schema.GroupResource{Group: "arc", Resource: "testobj"}))
+		Expect(singularResource(scheme, &discoveryTestObj{}, gr)).To(Equal(schema.GroupResource{Group: "arc", Resource: "testobject"}))
+	})
+
+	It("should only wrap stores of objects that declare short names or categories", func() {
+		store := &genericregistry.Store{}
+		Expect(WithDiscoveryInfo(store, &testObj{})).To(BeIdenticalTo(store))
+
+		storage := WithDiscoveryInfo(store, &discoveryTestObj{})
+		Expect(storage).To(BeAssignableToTypeOf(&DiscoveryStore{}))
+		Expect(storage.(*DiscoveryStore).ShortNames()).To(Equal([]string{"to"}))
+		Expect(storage.(*DiscoveryStore).Categories()).To(Equal([]string{"all"}))
+	})
+
+	It("should return the registry store of storage", func() {
+		store := &genericregistry.Store{}
+		for _, storage := range []Storage{store, &DiscoveryStore{Store: store}} {
+			s, ok := RegistryStore(storage)
+			Expect(ok).To(BeTrue())
+			Expect(s).To(BeIdenticalTo(store))
+		}
+		_, ok := RegistryStore(&testStorage{})
+		Expect(ok).To(BeFalse())
+	})
+})
+
+// testStorage is a storage that is not backed by a registry store.
+type testStorage struct{}
+
+func (s *testStorage) New() runtime.Object { return &testObj{} }
+func (s *testStorage) Destroy()            {}
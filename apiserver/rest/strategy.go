@@ -5,6 +5,9 @@ package rest
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"go.opendefense.cloud/kit/apiserver/resource"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -17,6 +20,7 @@ import (
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/utils/ptr"
 )
 
 // Strategy defines the set of hooks and behaviors used by the API server for resource storage operations.
@@ -32,6 +36,10 @@ type Strategy interface {
 
 var _ Strategy = DefaultStrategy{}
 
+// DefaultStrategy deletes objects gracefully if they implement GracefulDeleteChecker or
+// DeletionGracePeriodProvider, the generic registry checks strategies for rest.RESTGracefulDeleteStrategy.
+var _ rest.RESTGracefulDeleteStrategy = DefaultStrategy{}
+
 // DefaultStrategy is a generic implementation of Strategy.
 // It delegates most behaviors to interfaces implemented by the underlying Object, if present.
 // If the Object does not implement an override interface, DefaultStrategy provides a fallback.
@@ -42,17 +50,41 @@ type DefaultStrategy struct {
 	runtime.ObjectTyper
 	// TableConvertor is used for table output if the object does not implement TableConverter.
 	TableConvertor rest.TableConvertor
+	// GroupResource labels the metrics of the strategy.
+	GroupResource schema.GroupResource
+	// Rules validates objects with the CEL validation rules of the object, on top of Validater and ValidateUpdater.
+	Rules *RuleValidator
 }
 
 // NewDefaultStrategy constructs a DefaultStrategy for a given resource type.
 // obj: a sample instance of the resource
 // objTyper: type information provider
-// gr: group/resource descriptor for table conversion
+// gr: group/resource descriptor for table conversion and metrics
+//
+// If obj implements PrinterColumnsProvider, tables are built from its columns, and if it implements
+// ValidationRulesProvider, its rules are compiled. It panics if they are invalid, as they are declared in code.
 func NewDefaultStrategy(obj runtime.Object, objTyper runtime.ObjectTyper, gr schema.GroupResource) *DefaultStrategy {
+	RegisterMetrics()
+	tableConvertor := rest.NewDefaultTableConvertor(gr)
+	if p, ok := obj.(PrinterColumnsProvider); ok {
+		var err error
+		if tableConvertor, err = NewPrinterColumnsTableConvertor(p.PrinterColumns()); err != nil {
+			panic(fmt.Sprintf("invalid printer columns of %s: %v", gr, err))
+		}
+	}
+	var rules *RuleValidator
+	if p, ok := obj.(ValidationRulesProvider); ok {
+		var err error
+		if rules, err = NewRuleValidator(p.ValidationRules()); err != nil {
+			panic(fmt.Sprintf("invalid validation rules of %s: %v", gr, err))
+		}
+	}
 	return &DefaultStrategy{
 		Object:         obj,
 		ObjectTyper:    objTyper,
-		TableConvertor: rest.NewDefaultTableConvertor(gr),
+		TableConvertor: tableConvertor,
+		GroupResource:  gr,
+		Rules:          rules,
 	}
 }
 
@@ -78,8 +110,17 @@ func (d DefaultStrategy) NamespaceScoped() bool {
 	return true
 }
 
-// PrepareForCreate normalizes the object before creation, delegating to PrepareForCreater if implemented.
-func (DefaultStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+// PrepareForCreate normalizes the object before creation. It applies the defaults of Defaulter, adds the
+// finalizers of DefaultFinalizersProvider and delegates to PrepareForCreater if implemented.
+func (d DefaultStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	defer observeHook(ctx, d.GroupResource, "create", "PrepareForCreate", time.Now())
+	// Request bodies of other versions are defaulted before conversion, so defaults are applied again.
+	if v, ok := obj.(Defaulter); ok {
+		v.Default()
+	}
+	if v, ok := obj.(DefaultFinalizersProvider); ok {
+		addFinalizers(obj, v.DefaultFinalizers())
+	}
 	if v, ok := obj.(PrepareForCreater); ok {
 		v.PrepareForCreate(ctx)
 	}
@@ -87,8 +128,18 @@ func (DefaultStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object)
 
 // PrepareForUpdate normalizes the object before update.
 // If the object has a status subresource, status is copied from old to new.
-// If PrepareForUpdater is implemented, it is called to further normalize.
-func (DefaultStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+// The defaults of Defaulter are applied, the finalizers of DefaultFinalizersProvider are added unless the object
+// is being deleted, and if PrepareForUpdater is implemented, it is called to further normalize.
+func (d DefaultStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	defer observeHook(ctx, d.GroupResource, "update", "PrepareForUpdate", time.Now())
+	if v, ok := obj.(Defaulter); ok {
+		v.Default()
+	}
+	if v, ok := obj.(DefaultFinalizersProvider); ok {
+		if accessor, err := meta.Accessor(old); err == nil && accessor.GetDeletionTimestamp() == nil {
+			addFinalizers(obj, v.DefaultFinalizers())
+		}
+	}
 	if v, ok := obj.(resource.ObjectWithStatusSubResource); ok {
 		// Copy status from old to new to avoid spec-only updates modifying status.
 		old.(resource.ObjectWithStatusSubResource).CopyStatusTo(v)
@@ -98,12 +149,16 @@ func (DefaultStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Ob
 	}
 }
 
-// Validate delegates to the object's Validater interface if present, otherwise returns no errors.
-func (DefaultStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+// Validate delegates to the object's Validater interface if present and evaluates the validation rules.
+func (d DefaultStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	defer observeHook(ctx, d.GroupResource, "create", "Validate", time.Now())
+	errs := field.ErrorList{}
 	if v, ok := obj.(Validater); ok {
-		return v.Validate(ctx)
+		errs = append(errs, v.Validate(ctx)...)
 	}
-	return field.ErrorList{}
+	errs = append(errs, d.Rules.Validate(ctx, obj, nil)...)
+	recordValidationFailures(ctx, d.GroupResource, "create", errs)
+	return errs
 }
 
 // AllowCreateOnUpdate returns true if the object allows creation via update (PUT), using AllowCreateOnUpdater if present.
@@ -135,26 +190,73 @@ func (DefaultStrategy) Canonicalize(obj runtime.Object) {
 	}
 }
 
-// ValidateUpdate delegates to the object's ValidateUpdater interface if present, otherwise returns no errors.
-func (DefaultStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+// ValidateUpdate delegates to the object's ValidateUpdater interface if present and evaluates the validation
+// rules, including transition rules.
+func (d DefaultStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	defer observeHook(ctx, d.GroupResource, "update", "ValidateUpdate", time.Now())
+	errs := field.ErrorList{}
 	if v, ok := obj.(ValidateUpdater); ok {
-		return v.ValidateUpdate(ctx, old)
+		errs = append(errs, v.ValidateUpdate(ctx, old)...)
+	}
+	errs = append(errs, d.Rules.Validate(ctx, obj, old)...)
+	recordValidationFailures(ctx, d.GroupResource, "update", errs)
+	return errs
+}
+
+// CheckGracefulDelete calls PrepareForDeleter if implemented and returns true if the object is deleted gracefully.
+// Objects are deleted gracefully if GracefulDeleteChecker returns true or if they implement DeletionGracePeriodProvider,
+// which defaults the grace period of the options. Otherwise they are deleted immediately, or once their finalizers
+// are removed.
+func (d DefaultStrategy) CheckGracefulDelete(ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) bool {
+	defer observeHook(ctx, d.GroupResource, "delete", "CheckGracefulDelete", time.Now())
+	if v, ok := obj.(PrepareForDeleter); ok {
+		v.PrepareForDelete(ctx, options)
+	}
+	graceful := false
+	if v, ok := obj.(DeletionGracePeriodProvider); ok {
+		if options.GracePeriodSeconds == nil {
+			options.GracePeriodSeconds = ptr.To(v.DeletionGracePeriodSeconds())
+		}
+		graceful = true
+	}
+	if v, ok := obj.(GracefulDeleteChecker); ok {
+		graceful = v.CheckGracefulDelete(ctx, options)
+	}
+	return graceful
+}
+
+// addFinalizers adds the finalizers obj does not have yet.
+func addFinalizers(obj runtime.Object, finalizers []string) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	current := accessor.GetFinalizers()
+	for _, f := range finalizers {
+		if !slices.Contains(current, f) {
+			current = append(current, f)
+		}
 	}
-	return field.ErrorList{}
+	accessor.SetFinalizers(current)
 }
 
 // Match returns a SelectionPredicate for filtering resources by label and field selectors.
-func (DefaultStrategy) Match(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+// Selectable fields of Object are indexed, so exact matches on them are served from the watch cache indexes.
+func (d DefaultStrategy) Match(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+	// Invalid selectable fields are rejected by NewStore.
+	indexFields, _ := SelectableFieldNames(d.Object)
 	return storage.SelectionPredicate{
-		Label:    label,
-		Field:    field,
-		GetAttrs: GetAttrs,
+		Label:       label,
+		Field:       field,
+		GetAttrs:    GetAttrs,
+		IndexFields: indexFields,
 	}
 }
 
 // ConvertToTable returns a Table representation of the object, using TableConverter if implemented.
 func (d DefaultStrategy) ConvertToTable(
 	ctx context.Context, obj runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	defer observeHook(ctx, d.GroupResource, "get", "ConvertToTable", time.Now())
 
 	if c, ok := obj.(TableConverter); ok {
 		table, err := c.ConvertToTable(ctx, tableOptions)
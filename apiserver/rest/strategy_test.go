@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 // testObj is a small helper type used to implement several of the
@@ -146,6 +147,33 @@ type allowUnconditional struct {
 
 func (a *allowUnconditional) AllowUnconditionalUpdate() bool { return true }
 
+// graceful implements DeletionGracePeriodProvider and PrepareForDeleter
+type graceful struct {
+	testObj
+}
+
+func (g *graceful) DeletionGracePeriodSeconds() int64 { return 30 }
+
+func (g *graceful) PrepareForDelete(ctx context.Context, options *metav1.DeleteOptions) {
+	g.Status = "Terminating"
+}
+
+// gracefulChecker implements GracefulDeleteChecker
+type gracefulChecker struct {
+	testObj
+}
+
+func (g *gracefulChecker) CheckGracefulDelete(ctx context.Context, options *metav1.DeleteOptions) bool {
+	return g.Labels["graceful"] == "true" && options.GracePeriodSeconds != nil
+}
+
+// finalized implements DefaultFinalizersProvider
+type finalized struct {
+	testObj
+}
+
+func (f *finalized) DefaultFinalizers() []string { return []string{"arc/cleanup"} }
+
 var _ = Describe("DefaultStrategy", func() {
 	It("should use NameGenerator for GenerateName", func() {
 		ds := DefaultStrategy{Object: &nameGen{}}
@@ -173,6 +201,48 @@ var _ = Describe("DefaultStrategy", func() {
 		Expect(obj.Flag).To(BeTrue())
 	})
 
+	It("should delete gracefully with the default grace period and call PrepareForDeleter", func() {
+		ds := DefaultStrategy{}
+		obj := &graceful{}
+		options := &metav1.DeleteOptions{}
+		Expect(ds.CheckGracefulDelete(context.Background(), obj, options)).To(BeTrue())
+		Expect(options.GracePeriodSeconds).To(Equal(ptr.To[int64](30)))
+		Expect(obj.Status).To(Equal("Terminating"))
+
+		options = &metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](5)}
+		Expect(ds.CheckGracefulDelete(context.Background(), obj, options)).To(BeTrue())
+		Expect(options.GracePeriodSeconds).To(Equal(ptr.To[int64](5)))
+
+		Expect(ds.CheckGracefulDelete(context.Background(), &testObj{}, &metav1.DeleteOptions{})).To(BeFalse())
+	})
+
+	It("should delegate CheckGracefulDelete to GracefulDeleteChecker", func() {
+		ds := DefaultStrategy{}
+		obj := &gracefulChecker{}
+		options := &metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](5)}
+		Expect(ds.CheckGracefulDelete(context.Background(), obj, options)).To(BeFalse())
+		obj.Labels = map[string]string{"graceful": "true"}
+		Expect(ds.CheckGracefulDelete(context.Background(), obj, options)).To(BeTrue())
+	})
+
+	It("should add the default finalizers on create and update until the object is deleted", func() {
+		ds := DefaultStrategy{}
+		obj := &finalized{testObj: testObj{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}}}
+		ds.PrepareForCreate(context.Background(), obj)
+		Expect(obj.Finalizers).To(Equal([]string{"other", "arc/cleanup"}))
+		ds.PrepareForCreate(context.Background(), obj)
+		Expect(obj.Finalizers).To(Equal([]string{"other", "arc/cleanup"}))
+
+		obj = &finalized{}
+		ds.PrepareForUpdate(context.Background(), obj, &finalized{})
+		Expect(obj.Finalizers).To(Equal([]string{"arc/cleanup"}))
+
+		obj = &finalized{}
+		now := metav1.Now()
+		ds.PrepareForUpdate(context.Background(), obj, &finalized{testObj: testObj{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}}})
+		Expect(obj.Finalizers).To(BeEmpty())
+	})
+
 	It("should delegate Validate and ValidateUpdate to object", func() {
 		obj := &testObj{}
 		ds := DefaultStrategy{}
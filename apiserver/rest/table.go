@@ -0,0 +1,173 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metatable "k8s.io/apimachinery/pkg/api/meta/table"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// PrinterColumn is a column of the table output of a resource, e.g. of kubectl get,
+// like an additional printer column of a CustomResourceDefinition.
+type PrinterColumn struct {
+	// Name is the header of the column.
+	Name string
+	// Type is the OpenAPI type of the values: integer, number, string, boolean or date.
+	// Dates are shown as the time since then, like the Age column.
+	Type string
+	// Format is the optional OpenAPI format of the values, e.g. name or int32.
+	Format string
+	// Description is shown as help of the column.
+	Description string
+	// Priority of the column. Columns with a priority greater than 0 are only shown with -o wide.
+	Priority int32
+	// JSONPath is evaluated on the object to get the value, e.g. .spec.replicas.
+	JSONPath string
+}
+
+// PrinterColumnsProvider can be implemented by objects to declare the columns of their table output.
+// The columns are shown between the Name and the Age column.
+type PrinterColumnsProvider interface {
+	// PrinterColumns returns the columns of the table output.
+	PrinterColumns() []PrinterColumn
+}
+
+// NewPrinterColumnsTableConvertor returns a TableConvertor that converts objects and lists to tables
+// with a Name column, the given columns and an Age column. It fails if a JSONPath is invalid.
+func NewPrinterColumnsTableConvertor(columns []PrinterColumn) (rest.TableConvertor, error) {
+	c := &printerColumnsTableConvertor{
+		headers: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+		},
+	}
+	for _, column := range columns {
+		path := jsonpath.New(column.Name)
+		if err := path.Parse(fmt.Sprintf("{%s}", column.JSONPath)); err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q of column %q: %w", column.JSONPath, column.Name, err)
+		}
+		path.AllowMissingKeys(true)
+
+		description := column.Description
+		if description == "" {
+			description = fmt.Sprintf("Column in JSONPath format: %s", column.JSONPath)
+		}
+		c.columns = append(c.columns, path)
+		c.headers = append(c.headers, metav1.TableColumnDefinition{
+			Name:        column.Name,
+			Type:        column.Type,
+			Format:      column.Format,
+			Description: description,
+			Priority:    column.Priority,
+		})
+	}
+	c.headers = append(c.headers, metav1.TableColumnDefinition{
+		Name: "Age", Type: "date", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"],
+	})
+	return c, nil
+}
+
+// printerColumnsTableConvertor converts objects to tables with JSONPath columns.
+type printerColumnsTableConvertor struct {
+	headers []metav1.TableColumnDefinition
+	columns []*jsonpath.JSONPath
+}
+
+// ConvertToTable converts an object or a list of objects to a table.
+func (c *printerColumnsTableConvertor) ConvertToTable(ctx context.Context, obj runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{}
+	if opts, ok := tableOptions.(*metav1.TableOptions); !ok || opts == nil || !opts.NoHeaders {
+		table.ColumnDefinitions = c.headers
+	}
+	if m, err := meta.ListAccessor(obj); err == nil {
+		table.ResourceVersion = m.GetResourceVersion()
+		table.Continue = m.GetContinue()
+		table.RemainingItemCount = m.GetRemainingItemCount()
+	} else if m, err := meta.CommonAccessor(obj); err == nil {
+		table.ResourceVersion = m.GetResourceVersion()
+	}
+
+	var err error
+	buf := &bytes.Buffer{}
+	table.Rows, err = metatable.MetaToTableRow(obj, func(obj runtime.Object, _ metav1.Object, name, age string) ([]interface{}, error) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		cells := make([]interface{}, 0, len(c.headers))
+		cells = append(cells, name)
+		for i, column := range c.columns {
+			cells = append(cells, cellForColumn(column, c.headers[i+1].Type, content, buf))
+		}
+		return append(cells, age), nil
+	})
+	return table, err
+}
+
+// cellForColumn evaluates column on content and returns the cell of the given type, or nil if the value is
+// missing or of another type.
+func cellForColumn(column *jsonpath.JSONPath, columnType string, content map[string]interface{}, buf *bytes.Buffer) interface{} {
+	results, err := column.FindResults(content)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+	// Only the first value is shown, like for CustomResourceDefinitions.
+	value := results[0][0].Interface()
+	if value == nil {
+		return nil
+	}
+
+	switch columnType {
+	case "string":
+		defer buf.Reset()
+		if err := column.PrintResults(buf, []reflect.Value{reflect.ValueOf(value)}); err != nil {
+			return nil
+		}
+		return buf.String()
+	case "integer":
+		switch typed := value.(type) {
+		case int64:
+			return typed
+		case float64:
+			return int64(typed)
+		case json.Number:
+			if i, err := typed.Int64(); err == nil {
+				return i
+			}
+		}
+	case "number":
+		switch typed := value.(type) {
+		case int64:
+			return float64(typed)
+		case float64:
+			return typed
+		case json.Number:
+			if f, err := typed.Float64(); err == nil {
+				return f
+			}
+		}
+	case "boolean":
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case "date":
+		if s, ok := value.(string); ok {
+			var timestamp metav1.Time
+			if err := timestamp.UnmarshalQueryParameter(s); err != nil {
+				return "<invalid>"
+			}
+			return metatable.ConvertToHumanReadableDateType(timestamp)
+		}
+	}
+	return nil
+}
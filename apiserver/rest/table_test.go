@@ -0,0 +1,149 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// columnsObj implements PrinterColumnsProvider
+type columnsObj struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              columnsObjSpec `json:"spec"`
+}
+
+type columnsObjSpec struct {
+	Replicas int32       `json:"replicas"`
+	Ratio    float64     `json:"ratio"`
+	Paused   bool        `json:"paused"`
+	Image    string      `json:"image,omitempty"`
+	Since    metav1.Time `json:"since"`
+}
+
+func (c *columnsObj) DeepCopyObject() runtime.Object {
+	if c == nil {
+		return nil
+	}
+	copy := *c
+	return &copy
+}
+
+func (c *columnsObj) PrinterColumns() []PrinterColumn {
+	return []PrinterColumn{
+		{Name: "Replicas", Type: "integer", JSONPath: ".spec.replicas"},
+		{Name: "Ratio", Type: "number", JSONPath: ".spec.ratio"},
+		{Name: "Paused", Type: "boolean", JSONPath: ".spec.paused"},
+		{Name: "Image", Type: "string", Priority: 1, Description: "The image.", JSONPath: ".spec.image"},
+		{Name: "Since", Type: "date", JSONPath: ".spec.since"},
+	}
+}
+
+// columnsObjList is the list type of columnsObj
+type columnsObjList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []columnsObj `json:"items"`
+}
+
+func (c *columnsObjList) DeepCopyObject() runtime.Object {
+	if c == nil {
+		return nil
+	}
+	copy := *c
+	return &copy
+}
+
+var _ = Describe("PrinterColumns", func() {
+	var (
+		ds  *DefaultStrategy
+		obj *columnsObj
+	)
+
+	BeforeEach(func() {
+		ds = NewDefaultStrategy(&columnsObj{}, nil, schema.GroupResource{Group: "arc", Resource: "columnsobjs"})
+		obj = &columnsObj{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				ResourceVersion:   "7",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Hour)),
+			},
+			Spec: columnsObjSpec{
+				Replicas: 3,
+				Ratio:    0.5,
+				Paused:   true,
+				Image:    "nginx",
+				Since:    metav1.NewTime(time.Now().Add(-3 * time.Minute)),
+			},
+		}
+	})
+
+	It("should build columns from the JSONPaths of the object", func() {
+		tbl, err := ds.ConvertToTable(context.Background(), obj, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tbl.ResourceVersion).To(Equal("7"))
+
+		names := make([]string, 0, len(tbl.ColumnDefinitions))
+		for _, column := range tbl.ColumnDefinitions {
+			names = append(names, column.Name)
+		}
+		Expect(names).To(Equal([]string{"Name", "Replicas", "Ratio", "Paused", "Image", "Since", "Age"}))
+		Expect(tbl.ColumnDefinitions[1].Description).To(Equal("Column in JSONPath format: .spec.replicas"))
+		Expect(tbl.ColumnDefinitions[4].Description).To(Equal("The image."))
+		Expect(tbl.ColumnDefinitions[4].Priority).To(BeEquivalentTo(1))
+
+		Expect(tbl.Rows).To(HaveLen(1))
+		Expect(tbl.Rows[0].Cells).To(Equal([]interface{}{"foo", int64(3), 0.5, true, "nginx", "3m", "5h"}))
+		Expect(tbl.Rows[0].Object.Object).To(Equal(obj))
+	})
+
+	It("should build a row per item of lists", func() {
+		other := obj.DeepCopyObject().(*columnsObj)
+		other.Name = "bar"
+		other.Spec.Image = ""
+		list := &columnsObjList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "8", Continue: "next"},
+			Items:    []columnsObj{*obj, *other},
+		}
+		tbl, err := ds.ConvertToTable(context.Background(), list, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tbl.ResourceVersion).To(Equal("8"))
+		Expect(tbl.Continue).To(Equal("next"))
+		Expect(tbl.Rows).To(HaveLen(2))
+		Expect(tbl.Rows[0].Cells[0]).To(Equal("foo"))
+		Expect(tbl.Rows[1].Cells[0]).To(Equal("bar"))
+		// Missing values are empty cells.
+		Expect(tbl.Rows[1].Cells[4]).To(BeNil())
+	})
+
+	It("should omit the headers if requested", func() {
+		tbl, err := ds.ConvertToTable(context.Background(), obj, &metav1.TableOptions{NoHeaders: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tbl.ColumnDefinitions).To(BeEmpty())
+		Expect(tbl.Rows).To(HaveLen(1))
+	})
+
+	It("should leave cells of another type empty", func() {
+		c, err := NewPrinterColumnsTableConvertor([]PrinterColumn{
+			{Name: "Image", Type: "integer", JSONPath: ".spec.image"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		tbl, err := c.ConvertToTable(context.Background(), obj, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tbl.Rows[0].Cells).To(Equal([]interface{}{"foo", nil, "5h"}))
+	})
+
+	It("should reject invalid JSONPaths", func() {
+		_, err := NewPrinterColumnsTableConvertor([]PrinterColumn{{Name: "Bad", Type: "string", JSONPath: ".spec[[image"}})
+		Expect(err).To(HaveOccurred())
+	})
+})
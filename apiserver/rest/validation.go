@@ -0,0 +1,212 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/interpreter"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	apiservercel "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+)
+
+// ValidationRule is a CEL validation rule of a resource type, like x-kubernetes-validations of
+// CustomResourceDefinitions.
+type ValidationRule struct {
+	// Rule is the CEL expression that must evaluate to true, e.g. self.minReplicas <= self.maxReplicas.
+	// self is the value at FieldPath, oldSelf the value at FieldPath of the old object on update.
+	// Rules using oldSelf are transition rules, which are only evaluated on update if the old object
+	// has a value at FieldPath.
+	Rule string
+	// FieldPath is the dot-separated path of the value the rule validates, e.g. spec.replicas, or empty
+	// for the whole object. Rules are only evaluated if the object has a value at the path, and their
+	// errors are reported at it.
+	FieldPath string
+	// Message is the error message if the rule fails. It defaults to "failed rule: " followed by the rule.
+	Message string
+	// Reason is the type of the error if the rule fails, one of field.ErrorTypeInvalid, the default,
+	// field.ErrorTypeForbidden, field.ErrorTypeRequired and field.ErrorTypeDuplicate.
+	Reason field.ErrorType
+}
+
+// ValidationRulesProvider can be implemented by objects to validate them with CEL rules besides Validater
+// and ValidateUpdater, so they can be declared without Go code.
+type ValidationRulesProvider interface {
+	// ValidationRules returns the CEL validation rules of the object. The rules must not depend on the
+	// object, they are compiled once when the resource is registered.
+	ValidationRules() []ValidationRule
+}
+
+// RuleValidator validates objects with compiled ValidationRules, see NewRuleValidator.
+type RuleValidator struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	ValidationRule
+	fields     []string
+	path       *field.Path
+	program    cel.Program
+	transition bool
+}
+
+// ruleEnv returns the CEL environment of validation rules, the one of the Kubernetes API server, including
+// its libraries e.g. for quantities and URLs, with the variables self and oldSelf.
+var ruleEnv = sync.OnceValues(func() (*cel.Env, error) {
+	envSet, err := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true).Extend(
+		environment.VersionedOptions{
+			IntroducedVersion: version.MajorMinor(1, 0),
+			EnvOptions: []cel.EnvOption{
+				cel.Variable("self", cel.DynType),
+				cel.Variable("oldSelf", cel.DynType),
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return envSet.NewExpressionsEnv(), nil
+})
+
+// NewRuleValidator compiles rules into a RuleValidator. It fails if a rule does not compile or cannot
+// evaluate to a bool, or if its field path or reason is invalid.
+func NewRuleValidator(rules []ValidationRule) (*RuleValidator, error) {
+	env, err := ruleEnv()
+	if err != nil {
+		return nil, err
+	}
+	v := &RuleValidator{}
+	for _, rule := range rules {
+		c := compiledRule{ValidationRule: rule}
+		if rule.FieldPath != "" {
+			c.fields = strings.Split(rule.FieldPath, ".")
+			if slices.Contains(c.fields, "") {
+				return nil, fmt.Errorf("rule %q: invalid field path %q: must be a dot-separated path, e.g. spec.replicas", rule.Rule, rule.FieldPath)
+			}
+			c.path = field.NewPath(c.fields[0], c.fields[1:]...)
+		}
+		if c.Reason == "" {
+			c.Reason = field.ErrorTypeInvalid
+		}
+		if !slices.Contains([]field.ErrorType{field.ErrorTypeInvalid, field.ErrorTypeForbidden, field.ErrorTypeRequired, field.ErrorTypeDuplicate}, c.Reason) {
+			return nil, fmt.Errorf("rule %q: unsupported reason %q", rule.Rule, c.Reason)
+		}
+		if c.Message == "" {
+			c.Message = "failed rule: " + rule.Rule
+		}
+
+		ast, issues := env.Compile(rule.Rule)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Rule, issues.Err())
+		}
+		if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+			return nil, fmt.Errorf("rule %q: must evaluate to a bool instead of %s", rule.Rule, t)
+		}
+		for _, ref := range ast.NativeRep().ReferenceMap() {
+			if ref.Name == "oldSelf" {
+				c.transition = true
+			}
+		}
+		c.program, err = env.Program(ast,
+			cel.EvalOptions(cel.OptOptimize, cel.OptTrackCost),
+			cel.CostLimit(apiservercel.PerCallLimit),
+			cel.InterruptCheckFrequency(apiservercel.CheckFrequency),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Rule, err)
+		}
+		v.rules = append(v.rules, c)
+	}
+	return v, nil
+}
+
+// Validate evaluates the rules on obj and returns the errors of failed rules. old is the old object on
+// update, and nil on create, in which case transition rules are skipped. The evaluation of a rule and of
+// all rules of a call are limited to the cost limits of CustomResourceDefinitions. A nil RuleValidator
+// has no rules.
+func (v *RuleValidator) Validate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	if v == nil || len(v.rules) == 0 {
+		return nil
+	}
+	self, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, err)}
+	}
+	var oldSelf map[string]interface{}
+	if old != nil {
+		if oldSelf, err = runtime.DefaultUnstructuredConverter.ToUnstructured(old); err != nil {
+			return field.ErrorList{field.InternalError(nil, err)}
+		}
+	}
+
+	var errs field.ErrorList
+	budget := int64(apiservercel.RuntimeCELCostBudget)
+	for _, rule := range v.rules {
+		value, ok, _ := unstructured.NestedFieldNoCopy(self, rule.fields...)
+		if !ok {
+			continue
+		}
+		activation := map[string]interface{}{"self": value}
+		if rule.transition {
+			oldValue, ok, _ := unstructured.NestedFieldNoCopy(oldSelf, rule.fields...)
+			if oldSelf == nil || !ok {
+				continue
+			}
+			activation["oldSelf"] = oldValue
+		}
+
+		out, details, err := rule.program.ContextEval(ctx, activation)
+		if details != nil && details.ActualCost() != nil {
+			budget -= int64(*details.ActualCost())
+		}
+		var cancelled interpreter.EvalCancelledError
+		switch {
+		case errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded:
+			errs = append(errs, field.Invalid(rule.path, valueType(value), "call cost exceeds limit for rule: "+rule.Message))
+		case err != nil:
+			errs = append(errs, field.Invalid(rule.path, valueType(value), fmt.Sprintf("rule evaluation error: %s: %v", rule.Rule, err)))
+		case out.Type() != types.BoolType:
+			errs = append(errs, field.Invalid(rule.path, valueType(value), fmt.Sprintf("rule evaluation error: %s: must evaluate to a bool instead of %s", rule.Rule, out.Type())))
+		case out != types.True:
+			errs = append(errs, &field.Error{Type: rule.Reason, Field: rule.path.String(), BadValue: valueType(value), Detail: rule.Message})
+		}
+		if budget < 0 {
+			errs = append(errs, field.Forbidden(nil, "validation failed due to running out of cost budget, no further validation rules will be run"))
+			break
+		}
+	}
+	return errs
+}
+
+// valueType returns the OpenAPI type of an unstructured value, which is reported instead of the value
+// in errors of rules, as the value may be a whole object.
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// rulesObj implements ValidationRulesProvider
+type rulesObj struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              rulesObjSpec `json:"spec"`
+}
+
+type rulesObjSpec struct {
+	Min   int64   `json:"min"`
+	Max   int64   `json:"max"`
+	Image string  `json:"image,omitempty"`
+	Items []int64 `json:"items,omitempty"`
+}
+
+func (r *rulesObj) DeepCopyObject() runtime.Object {
+	copy := *r
+	return &copy
+}
+
+func (r *rulesObj) ValidationRules() []ValidationRule {
+	return []ValidationRule{
+		{Rule: "self.spec.min <= self.spec.max"},
+		{Rule: "self.startsWith('registry/')", FieldPath: "spec.image", Message: "must be from the registry"},
+		{Rule: "self == oldSelf", FieldPath: "spec.image", Message: "image is immutable", Reason: field.ErrorTypeForbidden},
+	}
+}
+
+var _ = Describe("ValidationRules", func() {
+	var (
+		ctx context.Context
+		v   *RuleValidator
+		obj *rulesObj
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		v, err = NewRuleValidator((&rulesObj{}).ValidationRules())
+		Expect(err).ToNot(HaveOccurred())
+		obj = &rulesObj{Spec: rulesObjSpec{Min: 1, Max: 2, Image: "registry/nginx"}}
+	})
+
+	It("should accept valid objects", func() {
+		Expect(v.Validate(ctx, obj, nil)).To(BeEmpty())
+		Expect(v.Validate(ctx, obj, obj.DeepCopyObject())).To(BeEmpty())
+	})
+
+	It("should report failed rules at their field path", func() {
+		obj.Spec.Min = 3
+		obj.Spec.Image = "nginx"
+		errs := v.Validate(ctx, obj, nil)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+		Expect(errs[0].BadValue).To(Equal("object"))
+		Expect(errs[0].Detail).To(Equal("failed rule: self.spec.min <= self.spec.max"))
+		Expect(errs[1].Field).To(Equal("spec.image"))
+		Expect(errs[1].BadValue).To(Equal("string"))
+		Expect(errs[1].Detail).To(Equal("must be from the registry"))
+	})
+
+	It("should evaluate transition rules on update only", func() {
+		old := obj.DeepCopyObject().(*rulesObj)
+		obj.Spec.Image = "registry/httpd"
+		Expect(v.Validate(ctx, obj, nil)).To(BeEmpty())
+		errs := v.Validate(ctx, obj, old)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(errs[0].Field).To(Equal("spec.image"))
+		Expect(errs[0].Detail).To(Equal("image is immutable"))
+
+		// Rules are skipped without a value at their path.
+		old.Spec.Image = ""
+		Expect(v.Validate(ctx, obj, old)).To(BeEmpty())
+	})
+
+	It("should reject invalid rules", func() {
+		for _, rule := range []ValidationRule{
+			{Rule: "self.spec.min <="},
+			{Rule: "self.spec.min + 1"},
+			{Rule: "1"},
+			{Rule: "true", FieldPath: "spec..min"},
+			{Rule: "true", Reason: field.ErrorTypeNotFound},
+		} {
+			_, err := NewRuleValidator([]ValidationRule{rule})
+			Expect(err).To(HaveOccurred(), rule.Rule)
+		}
+	})
+
+	It("should report rules that do not evaluate to a bool", func() {
+		v, err := NewRuleValidator([]ValidationRule{{Rule: "self.min", FieldPath: "spec"}})
+		Expect(err).ToNot(HaveOccurred())
+		errs := v.Validate(ctx, obj, nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec"))
+		Expect(errs[0].Detail).To(ContainSubstring("must evaluate to a bool"))
+	})
+
+	It("should limit the cost of rules", func() {
+		v, err := NewRuleValidator([]ValidationRule{{Rule: "self.all(x, self.all(y, self.all(z, x + y + z >= 0)))", FieldPath: "spec.items"}})
+		Expect(err).ToNot(HaveOccurred())
+		obj.Spec.Items = make([]int64, 200)
+		errs := v.Validate(ctx, obj, nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.items"))
+		Expect(errs[0].Detail).To(HavePrefix("call cost exceeds limit for rule"))
+	})
+
+	It("should evaluate the rules on top of Validater in DefaultStrategy", func() {
+		ds := NewDefaultStrategy(&rulesObj{}, nil, schema.GroupResource{Group: "arc", Resource: "rulesobjs"})
+		Expect(ds.Validate(ctx, obj)).To(BeEmpty())
+		old := obj.DeepCopyObject()
+		obj.Spec.Image = "registry/httpd"
+		Expect(ds.ValidateUpdate(ctx, obj, old)).To(HaveLen(1))
+		Expect(DefaultStrategy{}.Validate(ctx, obj)).To(BeEmpty())
+	})
+})
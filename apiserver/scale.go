@@ -0,0 +1,168 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	"go.opendefense.cloud/kit/apiserver/resource"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	registryrest "k8s.io/apiserver/pkg/registry/rest"
+)
+
+// addScaleToScheme registers the autoscaling/v1 Scale served by scale subresources. The external type is
+// also registered as internal version, so requests need no conversion.
+func addScaleToScheme(scheme *runtime.Scheme) {
+	for _, gv := range []schema.GroupVersion{autoscalingv1.SchemeGroupVersion, {Group: autoscalingv1.GroupName, Version: runtime.APIVersionInternal}} {
+		if !scheme.Recognizes(gv.WithKind("Scale")) {
+			scheme.AddKnownTypes(gv, &autoscalingv1.Scale{})
+		}
+	}
+}
+
+// scaleFromObject returns the scale of obj.
+func scaleFromObject(obj runtime.Object) (*autoscalingv1.Scale, error) {
+	scalable, ok := obj.(resource.ObjectWithScaleSubResource)
+	if !ok {
+		return nil, fmt.Errorf("object of type %T has no scale subresource", obj)
+	}
+	specReplicas, statusReplicas, selector := scalable.GetScale()
+	om := scalable.GetObjectMeta()
+	return &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              om.Name,
+			Namespace:         om.Namespace,
+			UID:               om.UID,
+			ResourceVersion:   om.ResourceVersion,
+			CreationTimestamp: om.CreationTimestamp,
+		},
+		Spec:   autoscalingv1.ScaleSpec{Replicas: specReplicas},
+		Status: autoscalingv1.ScaleStatus{Replicas: statusReplicas, Selector: selector},
+	}, nil
+}
+
+var (
+	_ registryrest.Getter                   = &scaleStorage{}
+	_ registryrest.Updater                  = &scaleStorage{}
+	_ registryrest.GroupVersionKindProvider = &scaleStorage{}
+)
+
+// scaleStorage is the storage of the scale subresource of resources implementing
+// resource.ObjectWithScaleSubResource. It reads and writes the replicas of the parent object.
+type scaleStorage struct {
+	store *genericregistry.Store
+}
+
+// New returns an empty Scale.
+func (s *scaleStorage) New() runtime.Object {
+	return &autoscalingv1.Scale{}
+}
+
+// Destroy does nothing, the storage is shared with and destroyed by the parent resource.
+func (s *scaleStorage) Destroy() {}
+
+// GroupVersionKind returns autoscaling/v1 Scale, which is served instead of the kind of the parent resource.
+func (s *scaleStorage) GroupVersionKind(schema.GroupVersion) schema.GroupVersionKind {
+	return autoscalingv1.SchemeGroupVersion.WithKind("Scale")
+}
+
+// Get returns the scale of the parent object.
+func (s *scaleStorage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := s.store.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return scaleFromObject(obj)
+}
+
+// Update updates the desired replicas of the parent object. The resource version of the scale is used as
+// resource version of the parent object, so concurrent updates of the parent object are detected.
+func (s *scaleStorage) Update(ctx context.Context, name string, objInfo registryrest.UpdatedObjectInfo, createValidation registryrest.ValidateObjectFunc, updateValidation registryrest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	obj, _, err := s.store.Update(ctx, name, &scaleUpdatedObjectInfo{name: name, reqObjInfo: objInfo},
+		toScaleCreateValidation(createValidation), toScaleUpdateValidation(updateValidation), false, options)
+	if err != nil {
+		return nil, false, err
+	}
+	scale, err := scaleFromObject(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return scale, false, nil
+}
+
+// toScaleCreateValidation passes the scale of the object to admission.
+func toScaleCreateValidation(f registryrest.ValidateObjectFunc) registryrest.ValidateObjectFunc {
+	return func(ctx context.Context, obj runtime.Object) error {
+		scale, err := scaleFromObject(obj)
+		if err != nil {
+			return err
+		}
+		return f(ctx, scale)
+	}
+}
+
+// toScaleUpdateValidation passes the scales of the objects to admission.
+func toScaleUpdateValidation(f registryrest.ValidateObjectUpdateFunc) registryrest.ValidateObjectUpdateFunc {
+	return func(ctx context.Context, obj, old runtime.Object) error {
+		newScale, err := scaleFromObject(obj)
+		if err != nil {
+			return err
+		}
+		oldScale, err := scaleFromObject(old)
+		if err != nil {
+			return err
+		}
+		return f(ctx, newScale, oldScale)
+	}
+}
+
+// scaleUpdatedObjectInfo transforms the updated scale of a request to an update of the parent object.
+type scaleUpdatedObjectInfo struct {
+	name       string
+	reqObjInfo registryrest.UpdatedObjectInfo
+}
+
+// Preconditions returns the preconditions of the request.
+func (i *scaleUpdatedObjectInfo) Preconditions() *metav1.Preconditions {
+	return i.reqObjInfo.Preconditions()
+}
+
+// UpdatedObject returns a copy of the parent object with the replicas of the updated scale.
+func (i *scaleUpdatedObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	obj := oldObj.DeepCopyObject()
+	oldScale, err := scaleFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	newObj, err := i.reqObjInfo.UpdatedObject(ctx, oldScale)
+	if err != nil {
+		return nil, err
+	}
+	if newObj == nil {
+		return nil, apierrors.NewBadRequest("nil update passed to Scale")
+	}
+	scale, ok := newObj.(*autoscalingv1.Scale)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected input object type to be Scale, but %T", newObj))
+	}
+	if scale.Spec.Replicas < 0 {
+		return nil, apierrors.NewInvalid(autoscalingv1.SchemeGroupVersion.WithKind("Scale").GroupKind(), i.name, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "replicas"), scale.Spec.Replicas, "must be greater than or equal to 0"),
+		})
+	}
+
+	scalable := obj.(resource.ObjectWithScaleSubResource)
+	scalable.SetSpecReplicas(scale.Spec.Replicas)
+	// The resource version of the scale is a precondition of the update of the parent object.
+	scalable.GetObjectMeta().ResourceVersion = scale.ResourceVersion
+	return obj, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = Describe("Scale subresource", func() {
+	var (
+		ctx     context.Context
+		widgets dynamic.ResourceInterface
+		created *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server := startTestServer(newWidgetTestBuilder(Resource[*Widget](&Widget{}, testGroupVersion)))
+		DeferCleanup(server.Stop)
+		widgets = widgetClient(server)
+
+		obj := newWidget("foo", 1)
+		Expect(unstructured.SetNestedField(obj.Object, int64(2), "spec", "replicas")).To(Succeed())
+		var err error
+		created, err = widgets.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(created.Object, int64(1), "status", "replicas")).To(Succeed())
+		created, err = widgets.UpdateStatus(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should get the scale of the object", func() {
+		scale, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scale.GetAPIVersion()).To(Equal("autoscaling/v1"))
+		Expect(scale.GetKind()).To(Equal("Scale"))
+		Expect(scale.GetUID()).To(Equal(created.GetUID()))
+		Expect(scale.GetResourceVersion()).To(Equal(created.GetResourceVersion()))
+		Expect(scale.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeEquivalentTo(2))))
+		Expect(scale.Object).To(HaveKeyWithValue("status", And(
+			HaveKeyWithValue("replicas", BeEquivalentTo(1)),
+			HaveKeyWithValue("selector", "widget=foo"))))
+	})
+
+	It("should update the replicas of the object", func() {
+		scale, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(scale.Object, int64(5), "spec", "replicas")).To(Succeed())
+		scale, err = widgets.Update(ctx, scale, metav1.UpdateOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scale.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeEquivalentTo(5))))
+
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetResourceVersion()).To(Equal(scale.GetResourceVersion()))
+		Expect(got.Object).To(HaveKeyWithValue("spec", And(
+			HaveKeyWithValue("replicas", BeEquivalentTo(5)),
+			HaveKeyWithValue("size", BeEquivalentTo(1)))))
+
+		scale, err = widgets.Patch(ctx, "foo", types.MergePatchType, []byte(`{"spec":{"replicas":3}}`), metav1.PatchOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scale.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeEquivalentTo(3))))
+	})
+
+	It("should reject updates of a stale scale", func() {
+		scale, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(unstructured.SetNestedField(created.Object, int64(2), "spec", "size")).To(Succeed())
+		_, err = widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(unstructured.SetNestedField(scale.Object, int64(5), "spec", "replicas")).To(Succeed())
+		_, err = widgets.Update(ctx, scale, metav1.UpdateOptions{}, "scale")
+		Expect(err).To(Satisfy(apierrors.IsConflict))
+	})
+
+	It("should reject negative replicas", func() {
+		scale, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "scale")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(scale.Object, int64(-1), "spec", "replicas")).To(Succeed())
+		_, err = widgets.Update(ctx, scale, metav1.UpdateOptions{}, "scale")
+		Expect(err).To(Satisfy(apierrors.IsInvalid))
+	})
+})
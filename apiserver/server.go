@@ -0,0 +1,125 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/klog/v2"
+)
+
+// Server is an API server built by Builder.Build.
+// It can either be run in the foreground with Run or in the background with Start and Stop.
+// A server started in the background may terminate before it is ready, e.g. if its port is in
+// use, so callers waiting for Ready should also wait for Done.
+type Server struct {
+	// GenericAPIServer is the underlying generic API server. It can be used to add
+	// further post-start hooks or health checks before the server is run.
+	GenericAPIServer *genericapiserver.GenericAPIServer
+
+	ready chan struct{}
+	// failed receives the errors of components the server depends on, e.g. the embedded etcd.
+	// The server shuts down on the first error.
+	failed <-chan error
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// newServer wraps a GenericAPIServer into a Server.
+func newServer(server *genericapiserver.GenericAPIServer) *Server {
+	return &Server{
+		GenericAPIServer: server,
+		ready:            make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// Run prepares and runs the server, blocking until ctx is done and the server has shut down.
+// If a component the server depends on fails, e.g. the embedded etcd, the server shuts down and
+// Run returns the error of the component.
+func (s *Server) Run(ctx context.Context) error {
+	if s.failed == nil {
+		return s.GenericAPIServer.PrepareRun().RunWithContext(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failure error
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		select {
+		case err, ok := <-s.failed:
+			// The channel is closed when the component is stopped on shutdown.
+			if ok && err != nil {
+				klog.ErrorS(err, "Shutting down server after a component failed")
+				failure = err
+				cancel()
+			}
+		case <-ctx.Done():
+		}
+	}()
+	err := s.GenericAPIServer.PrepareRun().RunWithContext(ctx)
+	cancel()
+	<-watched
+	if failure != nil {
+		return failure
+	}
+	return err
+}
+
+// Start runs the server in the background until Stop is called or ctx is done.
+// Use Ready to wait for the server to serve requests and Done to notice if it terminated.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("server already started")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go func() {
+		defer close(s.done)
+		err := s.Run(ctx)
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}()
+	return nil
+}
+
+// Stop shuts down a server started with Start and waits until it has terminated.
+// It returns the error the server terminated with, if any.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return fmt.Errorf("server not started")
+	}
+
+	cancel()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Ready returns a channel that is closed once the server serves requests.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Done returns a channel that is closed once a server started with Start has terminated, either
+// because it was stopped or because it failed. Stop returns the error it terminated with.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+var _ = Describe("Server", func() {
+	It("should not be ready before it is started", func() {
+		s := newServer(nil)
+		Expect(s.Ready()).ToNot(BeClosed())
+	})
+
+	It("should serve requests until it is stopped", func() {
+		s, err := newTestBuilder().Build(context.Background(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.Start(context.Background())).To(Succeed())
+		Expect(s.Start(context.Background())).To(MatchError("server already started"))
+		Eventually(s.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+
+		client, err := kubernetes.NewForConfig(s.GenericAPIServer.LoopbackClientConfig)
+		Expect(err).ToNot(HaveOccurred())
+		body, err := client.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal("ok"))
+
+		Expect(s.Done()).ToNot(BeClosed())
+		Expect(s.Stop()).To(Succeed())
+		Expect(s.Done()).To(BeClosed())
+	})
+
+	It("should be done if it fails before it is ready", func() {
+		s, err := newTestBuilder().Build(context.Background(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		failed := make(chan error, 1)
+		failed <- errors.New("etcd crashed")
+		s.failed = failed
+		Expect(s.Start(context.Background())).To(Succeed())
+
+		Eventually(s.Done()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+		Expect(s.Stop()).To(MatchError("etcd crashed"))
+	})
+
+	It("should fail to stop a server that was not started", func() {
+		s := newServer(nil)
+		Expect(s.Stop()).To(MatchError("server not started"))
+	})
+
+	It("should shut down and report the error if a component fails", func() {
+		s, err := newTestBuilder().Build(context.Background(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		failed := make(chan error, 1)
+		s.failed = failed
+		Expect(s.Start(context.Background())).To(Succeed())
+		Eventually(s.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+
+		failed <- errors.New("etcd crashed")
+		Eventually(s.Done()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+		Expect(s.Stop()).To(MatchError("etcd crashed"))
+	})
+
+	It("should ignore components that are stopped on shutdown", func() {
+		s, err := newTestBuilder().Build(context.Background(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		failed := make(chan error)
+		close(failed)
+		s.failed = failed
+		Expect(s.Start(context.Background())).To(Succeed())
+		Eventually(s.Ready()).WithTimeout(wait.ForeverTestTimeout).Should(BeClosed())
+		Consistently(s.Done()).ShouldNot(BeClosed())
+		Expect(s.Stop()).To(Succeed())
+	})
+})
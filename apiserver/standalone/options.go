@@ -0,0 +1,226 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package standalone authenticates and authorizes requests of an API server that runs on its own,
+// without delegating to a kube-apiserver. Users authenticate with static tokens, client certificates
+// or OIDC and are authorized by a policy file.
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/pflag"
+	apiserverapi "k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/group"
+	"k8s.io/apiserver/pkg/authentication/request/anonymous"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/request/union"
+	"k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/token/tokenfile"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	"k8s.io/apiserver/pkg/authorization/path"
+	authorizationunion "k8s.io/apiserver/pkg/authorization/union"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+)
+
+// Options configures the authentication and authorization of a standalone API server.
+type Options struct {
+	// Anonymous allows unauthenticated requests as user "system:anonymous" in group "system:unauthenticated".
+	Anonymous bool
+	// TokenAuthFile is a CSV file of static bearer tokens in the format of kube-apiserver:
+	// token,user,uid,"group1,group2".
+	TokenAuthFile string
+	// ClientCAFile is a PEM bundle of CAs that sign client certificates. The common name of a
+	// certificate is the user name, its organizations are the groups.
+	ClientCAFile string
+	// OIDC authenticates bearer tokens issued by an OpenID Connect provider.
+	OIDC OIDCOptions
+	// PolicyFile is a YAML file with the Policy requests are authorized with.
+	// Without a policy, only members of "system:masters" and the AlwaysAllowPaths are authorized.
+	PolicyFile string
+	// AlwaysAllowPaths are HTTP paths every user is authorized for. A trailing "*" matches all paths with the prefix.
+	AlwaysAllowPaths []string
+}
+
+// OIDCOptions configures the authentication with ID tokens of an OpenID Connect provider.
+type OIDCOptions struct {
+	// IssuerURL is the https URL of the provider, tokens are only authenticated if it is set.
+	IssuerURL string
+	// ClientID is the audience tokens must be issued for.
+	ClientID string
+	// CAFile is a PEM bundle of CAs that sign the certificate of the provider. Defaults to the system CAs.
+	CAFile string
+	// UsernameClaim is the claim holding the user name.
+	UsernameClaim string
+	// UsernamePrefix is prepended to user names. If empty, user names other than emails are prefixed
+	// with the issuer URL and "#", "-" disables the prefix.
+	UsernamePrefix string
+	// GroupsClaim is the claim holding the groups of the user.
+	GroupsClaim string
+	// GroupsPrefix is prepended to group names.
+	GroupsPrefix string
+	// SigningAlgs are the allowed JOSE signing algorithms.
+	SigningAlgs []string
+}
+
+// NewOptions returns Options that allow anonymous requests to the health endpoints only.
+func NewOptions() *Options {
+	return &Options{
+		Anonymous:        true,
+		AlwaysAllowPaths: []string{"/healthz", "/readyz", "/livez"},
+		OIDC: OIDCOptions{
+			UsernameClaim: "sub",
+			SigningAlgs:   []string{"RS256"},
+		},
+	}
+}
+
+// AddFlags adds flags for the options to the given flag set.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Anonymous, "anonymous-auth", o.Anonymous, "Enables anonymous requests to the secure port. "+
+		"Anonymous requests have the username system:anonymous and the group system:unauthenticated.")
+	fs.StringVar(&o.TokenAuthFile, "token-auth-file", o.TokenAuthFile, "If set, the file that will be used to "+
+		"authenticate requests with static bearer tokens, in the CSV format token,user,uid,\"group1,group2\".")
+	fs.StringVar(&o.ClientCAFile, "client-ca-file", o.ClientCAFile, "If set, any request presenting a client certificate "+
+		"signed by one of the authorities in the client-ca-file is authenticated with an identity corresponding to "+
+		"the CommonName of the client certificate.")
+	fs.StringVar(&o.OIDC.IssuerURL, "oidc-issuer-url", o.OIDC.IssuerURL, "The URL of the OpenID issuer, only HTTPS "+
+		"scheme will be accepted. If set, it will be used to verify the OIDC JSON Web Token (JWT).")
+	fs.StringVar(&o.OIDC.ClientID, "oidc-client-id", o.OIDC.ClientID, "The client ID for the OpenID Connect client, "+
+		"must be set if oidc-issuer-url is set.")
+	fs.StringVar(&o.OIDC.CAFile, "oidc-ca-file", o.OIDC.CAFile, "If set, the OpenID server's certificate will be "+
+		"verified by one of the authorities in the oidc-ca-file, otherwise the host's root CA set will be used.")
+	fs.StringVar(&o.OIDC.UsernameClaim, "oidc-username-claim", o.OIDC.UsernameClaim, "The OpenID claim to use as the user name.")
+	fs.StringVar(&o.OIDC.UsernamePrefix, "oidc-username-prefix", o.OIDC.UsernamePrefix, "If provided, all usernames will "+
+		"be prefixed with this value. If not provided, username claims other than 'email' are prefixed by the issuer URL "+
+		"to avoid clashes. To skip any prefixing, provide the value '-'.")
+	fs.StringVar(&o.OIDC.GroupsClaim, "oidc-groups-claim", o.OIDC.GroupsClaim, "If provided, the name of a custom OpenID "+
+		"Connect claim for specifying user groups. The claim value is expected to be a string or array of strings.")
+	fs.StringVar(&o.OIDC.GroupsPrefix, "oidc-groups-prefix", o.OIDC.GroupsPrefix, "If provided, all groups will be "+
+		"prefixed with this value to prevent conflicts with other authentication strategies.")
+	fs.StringSliceVar(&o.OIDC.SigningAlgs, "oidc-signing-algs", o.OIDC.SigningAlgs, "Comma-separated list of allowed "+
+		"JOSE asymmetric signing algorithms.")
+	fs.StringVar(&o.PolicyFile, "authorization-policy-file", o.PolicyFile, "File with the rules requests are "+
+		"authorized with. Without a policy, only members of system:masters are authorized.")
+	fs.StringSliceVar(&o.AlwaysAllowPaths, "authorization-always-allow-paths", o.AlwaysAllowPaths, "A list of HTTP "+
+		"paths to skip during authorization, i.e. these are authorized without checking the policy.")
+}
+
+// Validate checks the options for errors.
+func (o *Options) Validate() []error {
+	var errs []error
+	if o.OIDC.IssuerURL != "" {
+		if u, err := url.Parse(o.OIDC.IssuerURL); err != nil || u.Scheme != "https" {
+			errs = append(errs, fmt.Errorf("--oidc-issuer-url %q must be a valid https URL", o.OIDC.IssuerURL))
+		}
+		if o.OIDC.ClientID == "" {
+			errs = append(errs, fmt.Errorf("--oidc-client-id must be set if --oidc-issuer-url is set"))
+		}
+		if o.OIDC.UsernameClaim == "" {
+			errs = append(errs, fmt.Errorf("--oidc-username-claim must not be empty"))
+		}
+	} else if o.OIDC.ClientID != "" {
+		errs = append(errs, fmt.Errorf("--oidc-issuer-url must be set if --oidc-client-id is set"))
+	}
+	return errs
+}
+
+// ApplyTo configures the authentication and authorization of a server. Client certificates are
+// requested by servingInfo. OIDC providers are discovered in the background until ctx is done.
+func (o *Options) ApplyTo(ctx context.Context, authn *genericapiserver.AuthenticationInfo, servingInfo *genericapiserver.SecureServingInfo, authz *genericapiserver.AuthorizationInfo) error {
+	var authenticators []authenticator.Request
+
+	if o.ClientCAFile != "" {
+		clientCA, err := dynamiccertificates.NewDynamicCAContentFromFile("client-ca-bundle", o.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client CA file: %w", err)
+		}
+		if err := authn.ApplyClientCert(clientCA, servingInfo); err != nil {
+			return fmt.Errorf("failed to apply client CA: %w", err)
+		}
+		authenticators = append(authenticators, x509.NewDynamic(clientCA.VerifyOptions, x509.CommonNameUserConversion))
+	}
+
+	if o.TokenAuthFile != "" {
+		tokens, err := tokenfile.NewCSV(o.TokenAuthFile)
+		if err != nil {
+			return fmt.Errorf("failed to load token auth file: %w", err)
+		}
+		authenticators = append(authenticators, bearertoken.New(tokens))
+	}
+
+	if o.OIDC.IssuerURL != "" {
+		tokens, err := o.OIDC.newAuthenticator(ctx)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, bearertoken.New(tokens))
+	}
+
+	authn.Authenticator = group.NewAuthenticatedGroupAdder(union.New(authenticators...))
+	if o.Anonymous {
+		authn.Authenticator = union.NewFailOnError(authn.Authenticator, anonymous.NewAuthenticator(nil))
+	}
+
+	alwaysAllowPaths, err := path.NewAuthorizer(o.AlwaysAllowPaths)
+	if err != nil {
+		return err
+	}
+	authorizers := []authorizer.Authorizer{alwaysAllowPaths, authorizerfactory.NewPrivilegedGroups(user.SystemPrivilegedGroup)}
+	if o.PolicyFile != "" {
+		policy, err := LoadPolicy(o.PolicyFile)
+		if err != nil {
+			return err
+		}
+		authorizers = append(authorizers, policy)
+	}
+	authz.Authorizer = authorizationunion.New(authorizers...)
+	return nil
+}
+
+// newAuthenticator returns an authenticator for the ID tokens of the provider, like the --oidc-* flags of kube-apiserver.
+func (o *OIDCOptions) newAuthenticator(ctx context.Context) (authenticator.Token, error) {
+	usernamePrefix := o.UsernamePrefix
+	switch {
+	case usernamePrefix == "-":
+		usernamePrefix = ""
+	case usernamePrefix == "" && o.UsernameClaim != "email":
+		usernamePrefix = o.IssuerURL + "#"
+	}
+	jwtAuthenticator := apiserverapi.JWTAuthenticator{
+		Issuer: apiserverapi.Issuer{
+			URL:       o.IssuerURL,
+			Audiences: []string{o.ClientID},
+		},
+		ClaimMappings: apiserverapi.ClaimMappings{
+			Username: apiserverapi.PrefixedClaimOrExpression{Claim: o.UsernameClaim, Prefix: &usernamePrefix},
+		},
+	}
+	if o.GroupsClaim != "" {
+		jwtAuthenticator.ClaimMappings.Groups = apiserverapi.PrefixedClaimOrExpression{Claim: o.GroupsClaim, Prefix: &o.GroupsPrefix}
+	}
+
+	opts := oidc.Options{
+		JWTAuthenticator:     jwtAuthenticator,
+		SupportedSigningAlgs: o.SigningAlgs,
+	}
+	if o.CAFile != "" {
+		ca, err := dynamiccertificates.NewDynamicCAContentFromFile("oidc-authenticator", o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OIDC CA file: %w", err)
+		}
+		opts.CAContentProvider = ca
+	}
+	tokens, err := oidc.New(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OIDC authenticator: %w", err)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package standalone
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/util/cert"
+)
+
+// newClientCert returns a CA bundle in PEM format and a client certificate signed by it.
+func newClientCert(commonName string, organizations ...string) ([]byte, *x509.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	ca, err := cert.NewSelfSignedCACert(cert.Config{CommonName: "test-ca"}, caKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName, Organization: organizations},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	Expect(err).ToNot(HaveOccurred())
+	clientCert, err := x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+	caPEM, err := cert.EncodeCertificates(ca)
+	Expect(err).ToNot(HaveOccurred())
+	return caPEM, clientCert
+}
+
+var _ = Describe("Options", func() {
+	It("should be configurable with flags", func() {
+		o := NewOptions()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		o.AddFlags(flags)
+		Expect(flags.Parse([]string{
+			"--anonymous-auth=false",
+			"--token-auth-file=tokens.csv",
+			"--client-ca-file=ca.pem",
+			"--oidc-issuer-url=https://issuer.example.com",
+			"--oidc-client-id=kit",
+			"--oidc-groups-claim=groups",
+			"--authorization-policy-file=policy.yaml",
+			"--authorization-always-allow-paths=/healthz",
+		})).To(Succeed())
+		Expect(o.Validate()).To(BeEmpty())
+		Expect(o.Anonymous).To(BeFalse())
+		Expect(o.TokenAuthFile).To(Equal("tokens.csv"))
+		Expect(o.ClientCAFile).To(Equal("ca.pem"))
+		Expect(o.OIDC).To(Equal(OIDCOptions{
+			IssuerURL:     "https://issuer.example.com",
+			ClientID:      "kit",
+			UsernameClaim: "sub",
+			GroupsClaim:   "groups",
+			SigningAlgs:   []string{"RS256"},
+		}))
+		Expect(o.PolicyFile).To(Equal("policy.yaml"))
+		Expect(o.AlwaysAllowPaths).To(Equal([]string{"/healthz"}))
+	})
+
+	It("should validate the OIDC options", func() {
+		o := NewOptions()
+		o.OIDC.IssuerURL = "http://issuer.example.com"
+		Expect(o.Validate()).To(ConsistOf(
+			MatchError(ContainSubstring("must be a valid https URL")),
+			MatchError(ContainSubstring("--oidc-client-id must be set")),
+		))
+
+		o = NewOptions()
+		o.OIDC.ClientID = "kit"
+		Expect(o.Validate()).To(ConsistOf(MatchError(ContainSubstring("--oidc-issuer-url must be set"))))
+	})
+
+	Describe("ApplyTo", func() {
+		var (
+			o           *Options
+			authn       genericapiserver.AuthenticationInfo
+			servingInfo *genericapiserver.SecureServingInfo
+			authz       genericapiserver.AuthorizationInfo
+			dir         string
+			clientCert  *x509.Certificate
+		)
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			var caPEM []byte
+			caPEM, clientCert = newClientCert("carol", "admins")
+			Expect(os.WriteFile(filepath.Join(dir, "ca.pem"), caPEM, 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "tokens.csv"), []byte(`alice-token,alice,1,"admins,dev"`+"\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(testPolicy), 0o600)).To(Succeed())
+
+			o = NewOptions()
+			o.TokenAuthFile = filepath.Join(dir, "tokens.csv")
+			o.ClientCAFile = filepath.Join(dir, "ca.pem")
+			o.PolicyFile = filepath.Join(dir, "policy.yaml")
+			authn = genericapiserver.AuthenticationInfo{}
+			servingInfo = &genericapiserver.SecureServingInfo{}
+			authz = genericapiserver.AuthorizationInfo{}
+		})
+
+		authenticate := func(req *http.Request) *authenticator.Response {
+			resp, ok, err := authn.Authenticator.AuthenticateRequest(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			return resp
+		}
+
+		authorize := func(u user.Info, path string) authorizer.Decision {
+			decision, _, err := authz.Authorizer.Authorize(context.Background(), authorizer.AttributesRecord{User: u, Verb: "get", Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			return decision
+		}
+
+		It("should authenticate requests with static tokens, client certificates and anonymously", func() {
+			Expect(o.ApplyTo(context.Background(), &authn, servingInfo, &authz)).To(Succeed())
+			Expect(servingInfo.ClientCA).ToNot(BeNil())
+
+			req := httptest.NewRequest(http.MethodGet, "/version", nil)
+			req.Header.Set("Authorization", "Bearer alice-token")
+			Expect(authenticate(req).User).To(And(
+				HaveField("GetName()", "alice"),
+				HaveField("GetGroups()", ConsistOf("admins", "dev", user.AllAuthenticated)),
+			))
+
+			req = httptest.NewRequest(http.MethodGet, "/version", nil)
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert}}
+			Expect(authenticate(req).User).To(And(
+				HaveField("GetName()", "carol"),
+				HaveField("GetGroups()", ConsistOf("admins", user.AllAuthenticated)),
+			))
+
+			req = httptest.NewRequest(http.MethodGet, "/version", nil)
+			Expect(authenticate(req).User).To(HaveField("GetName()", user.Anonymous))
+		})
+
+		It("should reject invalid tokens and unauthenticated requests without anonymous authentication", func() {
+			o.Anonymous = false
+			Expect(o.ApplyTo(context.Background(), &authn, servingInfo, &authz)).To(Succeed())
+
+			req := httptest.NewRequest(http.MethodGet, "/version", nil)
+			req.Header.Set("Authorization", "Bearer invalid")
+			_, ok, _ := authn.Authenticator.AuthenticateRequest(req)
+			Expect(ok).To(BeFalse())
+			_, ok, _ = authn.Authenticator.AuthenticateRequest(httptest.NewRequest(http.MethodGet, "/version", nil))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should authorize by the policy, the always allowed paths and system:masters", func() {
+			Expect(o.ApplyTo(context.Background(), &authn, servingInfo, &authz)).To(Succeed())
+			anonymous := &user.DefaultInfo{Name: user.Anonymous, Groups: []string{user.AllUnauthenticated}}
+			Expect(authorize(anonymous, "/healthz")).To(Equal(authorizer.DecisionAllow))
+			Expect(authorize(anonymous, "/version")).ToNot(Equal(authorizer.DecisionAllow))
+			Expect(authorize(&user.DefaultInfo{Name: "dave", Groups: []string{user.AllAuthenticated}}, "/version")).To(Equal(authorizer.DecisionAllow))
+			Expect(authorize(&user.DefaultInfo{Name: "root", Groups: []string{user.SystemPrivilegedGroup}}, "/metrics")).To(Equal(authorizer.DecisionAllow))
+		})
+
+		It("should only authorize system:masters without a policy", func() {
+			o.PolicyFile = ""
+			Expect(o.ApplyTo(context.Background(), &authn, servingInfo, &authz)).To(Succeed())
+			Expect(authorize(&user.DefaultInfo{Name: "dave", Groups: []string{user.AllAuthenticated}}, "/version")).ToNot(Equal(authorizer.DecisionAllow))
+			Expect(authorize(&user.DefaultInfo{Name: "root", Groups: []string{user.SystemPrivilegedGroup}}, "/version")).To(Equal(authorizer.DecisionAllow))
+		})
+
+		It("should fail on invalid files", func() {
+			o.PolicyFile = filepath.Join(dir, "missing.yaml")
+			Expect(o.ApplyTo(context.Background(), &authn, servingInfo, &authz)).To(MatchError(ContainSubstring("failed to read policy file")))
+		})
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"sigs.k8s.io/yaml"
+)
+
+// All matches every user, group, verb, API group, resource, name or namespace in a Rule.
+const All = "*"
+
+// Policy authorizes requests by a list of rules, like RBAC roles bound to their subjects.
+// A request is allowed if any rule allows it.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule allows its users and groups to perform the verbs on either resources or non-resource URLs.
+type Rule struct {
+	// Users the rule applies to.
+	Users []string `json:"users,omitempty"`
+	// Groups the rule applies to, e.g. "system:authenticated" for all authenticated users.
+	Groups []string `json:"groups,omitempty"`
+	// Verbs the rule allows, e.g. "get", "list", "watch", "create", "update", "patch" or "delete".
+	Verbs []string `json:"verbs"`
+	// APIGroups of the resources, "" is the core group.
+	APIGroups []string `json:"apiGroups,omitempty"`
+	// Resources the rule allows, subresources are given as "resource/subresource" or "*/subresource".
+	Resources []string `json:"resources,omitempty"`
+	// ResourceNames restricts the rule to objects with the given names. If empty, all objects are allowed.
+	ResourceNames []string `json:"resourceNames,omitempty"`
+	// Namespaces restricts the rule to the given namespaces. If empty, all namespaces and
+	// cluster-scoped resources are allowed.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NonResourceURLs the rule allows, e.g. "/version". A trailing "*" matches all URLs with the prefix.
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+}
+
+var _ authorizer.Authorizer = &Policy{}
+
+// LoadPolicy reads and validates the policy in the YAML file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// Validate checks that every rule has subjects and verbs, and either resources or non-resource URLs.
+func (p *Policy) Validate() error {
+	for i, rule := range p.Rules {
+		switch {
+		case len(rule.Users) == 0 && len(rule.Groups) == 0:
+			return fmt.Errorf("rules[%d]: users or groups must be set", i)
+		case len(rule.Verbs) == 0:
+			return fmt.Errorf("rules[%d]: verbs must be set", i)
+		case len(rule.Resources) > 0 && len(rule.NonResourceURLs) > 0:
+			return fmt.Errorf("rules[%d]: resources and nonResourceURLs are mutually exclusive", i)
+		case len(rule.Resources) > 0 && len(rule.APIGroups) == 0:
+			return fmt.Errorf("rules[%d]: apiGroups must be set for resources", i)
+		case len(rule.Resources) == 0 && len(rule.NonResourceURLs) == 0:
+			return fmt.Errorf("rules[%d]: resources or nonResourceURLs must be set", i)
+		}
+	}
+	return nil
+}
+
+// Authorize implements authorizer.Authorizer.
+func (p *Policy) Authorize(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	for _, rule := range p.Rules {
+		if rule.appliesTo(a.GetUser()) && rule.allows(a) {
+			return authorizer.DecisionAllow, "", nil
+		}
+	}
+	return authorizer.DecisionNoOpinion, "no policy rule allows the request", nil
+}
+
+// appliesTo returns whether u is one of the rule's users or in one of its groups.
+func (r *Rule) appliesTo(u user.Info) bool {
+	if u == nil {
+		return false
+	}
+	if matches(r.Users, u.GetName()) {
+		return true
+	}
+	for _, group := range u.GetGroups() {
+		if matches(r.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows returns whether the rule allows the request, ignoring its subjects.
+func (r *Rule) allows(a authorizer.Attributes) bool {
+	if !matches(r.Verbs, a.GetVerb()) {
+		return false
+	}
+	if !a.IsResourceRequest() {
+		return slices.ContainsFunc(r.NonResourceURLs, func(url string) bool {
+			if prefix, ok := strings.CutSuffix(url, "*"); ok {
+				return strings.HasPrefix(a.GetPath(), prefix)
+			}
+			return url == a.GetPath()
+		})
+	}
+	return matches(r.APIGroups, a.GetAPIGroup()) &&
+		r.allowsResource(a.GetResource(), a.GetSubresource()) &&
+		(len(r.ResourceNames) == 0 || (a.GetName() != "" && slices.Contains(r.ResourceNames, a.GetName()))) &&
+		(len(r.Namespaces) == 0 || matches(r.Namespaces, a.GetNamespace()))
+}
+
+// allowsResource returns whether the rule allows the resource and subresource, like RBAC.
+func (r *Rule) allowsResource(resource, subresource string) bool {
+	combined := resource
+	if subresource != "" {
+		combined = resource + "/" + subresource
+	}
+	for _, allowed := range r.Resources {
+		switch {
+		case allowed == All, allowed == combined:
+			return true
+		case subresource != "" && allowed == All+"/"+subresource:
+			return true
+		case subresource != "" && allowed == resource+"/"+All:
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether values contains value or All.
+func matches(values []string, value string) bool {
+	return slices.Contains(values, All) || slices.Contains(values, value)
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package standalone
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testPolicy = `rules:
+  - groups: ["admins"]
+    verbs: ["*"]
+    apiGroups: ["*"]
+    resources: ["*"]
+  - users: ["alice"]
+    verbs: ["get", "list"]
+    apiGroups: ["example.com"]
+    resources: ["widgets", "gadgets/status"]
+    namespaces: ["team-a"]
+  - users: ["bob"]
+    verbs: ["update"]
+    apiGroups: ["example.com"]
+    resources: ["*/scale"]
+    resourceNames: ["web"]
+  - groups: ["system:authenticated"]
+    verbs: ["get"]
+    nonResourceURLs: ["/version", "/apis/*"]
+`
+
+var _ = Describe("Policy", func() {
+	var policy *Policy
+
+	BeforeEach(func() {
+		path := filepath.Join(GinkgoT().TempDir(), "policy.yaml")
+		Expect(os.WriteFile(path, []byte(testPolicy), 0o600)).To(Succeed())
+		var err error
+		policy, err = LoadPolicy(path)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	resourceRequest := func(name string, groups []string, verb, namespace, resource, subresource, objectName string) authorizer.Attributes {
+		return authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: name, Groups: groups},
+			Verb:            verb,
+			Namespace:       namespace,
+			APIGroup:        "example.com",
+			Resource:        resource,
+			Subresource:     subresource,
+			Name:            objectName,
+			ResourceRequest: true,
+		}
+	}
+
+	nonResourceRequest := func(name string, groups []string, verb, path string) authorizer.Attributes {
+		return authorizer.AttributesRecord{
+			User: &user.DefaultInfo{Name: name, Groups: groups},
+			Verb: verb,
+			Path: path,
+		}
+	}
+
+	DescribeTable("should authorize requests by the matching rules",
+		func(a authorizer.Attributes, allowed bool) {
+			decision, _, err := policy.Authorize(context.Background(), a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision == authorizer.DecisionAllow).To(Equal(allowed))
+		},
+		Entry("admins everything", resourceRequest("carol", []string{"admins"}, "delete", "", "widgets", "status", "foo"), true),
+		Entry("alice allowed verb in namespace", resourceRequest("alice", nil, "list", "team-a", "widgets", "", ""), true),
+		Entry("alice other namespace", resourceRequest("alice", nil, "list", "team-b", "widgets", "", ""), false),
+		Entry("alice other verb", resourceRequest("alice", nil, "delete", "team-a", "widgets", "", "foo"), false),
+		Entry("alice subresource of allowed resource", resourceRequest("alice", nil, "get", "team-a", "widgets", "status", "foo"), false),
+		Entry("alice allowed subresource", resourceRequest("alice", nil, "get", "team-a", "gadgets", "status", "foo"), true),
+		Entry("bob wildcard subresource of named object", resourceRequest("bob", nil, "update", "team-b", "widgets", "scale", "web"), true),
+		Entry("bob other object", resourceRequest("bob", nil, "update", "team-b", "widgets", "scale", "db"), false),
+		Entry("bob without name", resourceRequest("bob", nil, "update", "team-b", "widgets", "scale", ""), false),
+		Entry("authenticated exact url", nonResourceRequest("dave", []string{user.AllAuthenticated}, "get", "/version"), true),
+		Entry("authenticated prefix url", nonResourceRequest("dave", []string{user.AllAuthenticated}, "get", "/apis/example.com"), true),
+		Entry("authenticated other url", nonResourceRequest("dave", []string{user.AllAuthenticated}, "get", "/metrics"), false),
+		Entry("unauthenticated url", nonResourceRequest(user.Anonymous, []string{user.AllUnauthenticated}, "get", "/version"), false),
+	)
+
+	DescribeTable("should reject invalid rules",
+		func(rule Rule, message string) {
+			Expect((&Policy{Rules: []Rule{rule}}).Validate()).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("without subjects", Rule{Verbs: []string{"get"}, NonResourceURLs: []string{"/"}}, "users or groups must be set"),
+		Entry("without verbs", Rule{Users: []string{"a"}, NonResourceURLs: []string{"/"}}, "verbs must be set"),
+		Entry("with resources and urls", Rule{Users: []string{"a"}, Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}, NonResourceURLs: []string{"/"}}, "mutually exclusive"),
+		Entry("without api groups", Rule{Users: []string{"a"}, Verbs: []string{"get"}, Resources: []string{"pods"}}, "apiGroups must be set"),
+		Entry("without resources or urls", Rule{Users: []string{"a"}, Verbs: []string{"get"}}, "resources or nonResourceURLs must be set"),
+	)
+
+	It("should reject unknown fields", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "policy.yaml")
+		Expect(os.WriteFile(path, []byte("rules:\n  - user: [alice]\n"), 0o600)).To(Succeed())
+		_, err := LoadPolicy(path)
+		Expect(err).To(MatchError(ContainSubstring("unknown field")))
+	})
+})
@@ -0,0 +1,16 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package standalone
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStandalone(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Standalone Suite")
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"fmt"
+
+	kitstorage "go.opendefense.cloud/kit/apiserver/storage"
+	"go.opendefense.cloud/kit/apiserver/storage/memory"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+)
+
+const (
+	// StorageBackendMemory is the --storage-backend that keeps all resources in memory, see WithInMemoryStorage.
+	StorageBackendMemory = "memory"
+	// StorageBackendSQLite is the --storage-backend that stores all resources in a SQLite database, see WithSQLiteStorage.
+	// It is only available if go.opendefense.cloud/kit/apiserver/storage/sqlite is imported.
+	StorageBackendSQLite = "sqlite"
+	// sqlitePackage is the package that registers the SQLite storage backend.
+	sqlitePackage = "go.opendefense.cloud/kit/apiserver/storage/sqlite"
+)
+
+// storageBackend returns a function that creates the storage backend selected with --storage-backend, or false
+// if it selects etcd. Besides the in-memory backend, backends registered with storage.RegisterBackend can be
+// selected, they are opened at --sqlite-path.
+func storageBackend(name string) (func(b *Builder) (kitstorage.Backend, error), bool) {
+	if name == StorageBackendMemory {
+		return func(*Builder) (kitstorage.Backend, error) {
+			return memory.New(), nil
+		}, true
+	}
+	factory, ok := kitstorage.LookupBackend(name)
+	if !ok {
+		return nil, false
+	}
+	return func(b *Builder) (kitstorage.Backend, error) {
+		return factory(b.sqlitePath)
+	}, true
+}
+
+// groupStorageConfig holds the storage settings that are specific to a single API group.
+type groupStorageConfig struct {
+	prefix          string
+	codec           runtime.Codec
+	encodeVersioner runtime.GroupVersioner
+	// resources overrides the codec and encode versioner of resources by name.
+	resources map[string]resourceStorageConfig
+}
+
+// resourceStorageConfig holds the storage settings of a resource that is stored in another version than its group.
+type resourceStorageConfig struct {
+	codec           runtime.Codec
+	encodeVersioner runtime.GroupVersioner
+}
+
+// groupRESTOptionsGetter wraps a RESTOptionsGetter and applies per-group storage prefixes,
+// codecs and encode versioners, so resources of several API groups can share one storage backend.
+type groupRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+	groups   map[string]groupStorageConfig
+}
+
+var _ generic.RESTOptionsGetter = &groupRESTOptionsGetter{}
+
+// GetRESTOptions returns the delegate's RESTOptions with the storage config of the resource's group applied.
+// Resources of unknown groups are passed through unchanged.
+func (g *groupRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource, example runtime.Object) (generic.RESTOptions, error) {
+	opts, err := g.delegate.GetRESTOptions(resource, example)
+	if err != nil {
+		return opts, err
+	}
+	cfg, ok := g.groups[resource.Group]
+	if !ok || opts.StorageConfig == nil {
+		return opts, nil
+	}
+
+	// Copy the storage config, it may be shared between resources.
+	storageConfig := *opts.StorageConfig
+	storageConfig.Prefix = cfg.prefix
+	storageConfig.Codec = cfg.codec
+	storageConfig.EncodeVersioner = cfg.encodeVersioner
+	if r, ok := cfg.resources[resource.Resource]; ok {
+		storageConfig.Codec = r.codec
+		storageConfig.EncodeVersioner = r.encodeVersioner
+	}
+	opts.StorageConfig = &storageConfig
+	return opts, nil
+}
+
+// etcdServersRESTOptionsGetter wraps a RESTOptionsGetter and stores resources on the etcd servers of
+// their --etcd-servers-overrides entry.
+type etcdServersRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+	servers  map[schema.GroupResource][]string
+}
+
+var _ generic.RESTOptionsGetter = &etcdServersRESTOptionsGetter{}
+
+func newEtcdServersRESTOptionsGetter(delegate generic.RESTOptionsGetter, overrides []genericoptions.EtcdServerOverride) *etcdServersRESTOptionsGetter {
+	servers := map[schema.GroupResource][]string{}
+	for _, override := range overrides {
+		servers[override.GroupResource] = override.Servers
+	}
+	return &etcdServersRESTOptionsGetter{delegate: delegate, servers: servers}
+}
+
+// GetRESTOptions returns the delegate's RESTOptions with the etcd servers of the resource's override.
+func (g *etcdServersRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource, example runtime.Object) (generic.RESTOptions, error) {
+	opts, err := g.delegate.GetRESTOptions(resource, example)
+	if err != nil {
+		return opts, err
+	}
+	servers, ok := g.servers[resource]
+	if !ok || opts.StorageConfig == nil {
+		return opts, nil
+	}
+
+	// Copy the storage config, it may be shared between resources.
+	storageConfig := *opts.StorageConfig
+	storageConfig.Transport.ServerList = servers
+	opts.StorageConfig = &storageConfig
+	return opts, nil
+}
+
+// backendRESTOptionsGetter wraps a RESTOptionsGetter and stores all resources in a storage backend instead of etcd.
+type backendRESTOptionsGetter struct {
+	delegate         generic.RESTOptionsGetter
+	backend          kitstorage.Backend
+	enableWatchCache bool
+	watchCacheSizes  map[schema.GroupResource]int
+}
+
+var _ generic.RESTOptionsGetter = &backendRESTOptionsGetter{}
+
+// GetRESTOptions returns the delegate's RESTOptions with a storage decorator for the backend.
+// Like for etcd, the watch cache is used unless it is disabled or its size is set to zero for the resource.
+func (g *backendRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource, example runtime.Object) (generic.RESTOptions, error) {
+	opts, err := g.delegate.GetRESTOptions(resource, example)
+	if err != nil {
+		return opts, err
+	}
+	cached := g.enableWatchCache
+	if size, ok := g.watchCacheSizes[resource]; ok && size <= 0 {
+		cached = false
+	}
+	opts.Decorator = kitstorage.NewStorageDecorator(g.backend, cached)
+	return opts, nil
+}
+
+// storageBackendUsage returns the usage of the --storage-backend flag.
+func storageBackendUsage() string {
+	usage := "The storage backend for persistence. Options: 'etcd3' (default)"
+	for _, name := range sets.List(sets.New(kitstorage.RegisteredBackends()...).Insert(StorageBackendMemory)) {
+		usage += fmt.Sprintf(", '%s'", name)
+	}
+	return usage + "."
+}
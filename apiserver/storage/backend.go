@@ -0,0 +1,141 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrCompacted is returned by a Backend if the requested revision has been compacted.
+	ErrCompacted = errors.New("required revision has been compacted")
+	// ErrFutureRevision is returned by a Backend if the requested revision is newer than its current revision.
+	ErrFutureRevision = errors.New("required revision is a future revision")
+)
+
+// KeyValue is a value stored in a Backend.
+type KeyValue struct {
+	// Key is the full key of the value.
+	Key string
+	// Value is the stored data.
+	Value []byte
+	// ModRevision is the revision of the last modification of the key.
+	ModRevision int64
+}
+
+// EventType is the type of a watch Event.
+type EventType int
+
+const (
+	// EventPut is sent when a key is created or updated.
+	EventPut EventType = iota
+	// EventDelete is sent when a key is deleted.
+	EventDelete
+	// EventProgress is sent in response to Backend.RequestProgress. It guarantees that all
+	// events up to Revision have been sent to the watcher.
+	EventProgress
+)
+
+// Event is a change of a key observed by a Backend watch.
+type Event struct {
+	Type EventType
+	// Key is the key that changed. It is empty for progress events.
+	Key string
+	// Value is the new value of the key. It is empty for delete and progress events.
+	Value []byte
+	// PrevValue is the value of the key before the change, or nil if the key was created.
+	PrevValue []byte
+	// Revision is the revision of the change.
+	Revision int64
+}
+
+// WriteResult is the result of a conditional write to a Backend.
+type WriteResult struct {
+	// Succeeded is false if the write was rejected because the key was modified concurrently.
+	Succeeded bool
+	// Revision is the revision of the write, or the current revision of the Backend if the write failed.
+	Revision int64
+	// Current is the current value of the key if the write failed, or nil if the key does not exist.
+	Current *KeyValue
+}
+
+// Backend is a revisioned key-value store that objects are persisted in.
+//
+// Every write increments a store-wide revision, which is exposed as the resourceVersion of
+// the written object. Like etcd, an empty Backend is at revision 1, as the watch cache rejects
+// lists at revision 0. Backends keep the history of changes since their last compaction, so
+// lists and watches can be served at older revisions.
+type Backend interface {
+	// Get returns the current value of key, or nil if it does not exist, along with the current revision.
+	Get(ctx context.Context, key string) (*KeyValue, int64, error)
+	// List returns up to limit values (all if limit is 0) whose keys have the given prefix and are
+	// not less than startKey, ordered by key. It returns the values as of revision rev, or as of the
+	// current revision if rev is 0, along with the total number of keys from startKey to the end of
+	// the prefix and the revision that was listed.
+	List(ctx context.Context, prefix, startKey string, limit, rev int64) ([]*KeyValue, int64, int64, error)
+	// Count returns the number of keys with the given prefix.
+	Count(ctx context.Context, prefix string) (int64, error)
+	// Put sets key to value if the ModRevision of key equals rev. A rev of 0 requires key to not exist.
+	Put(ctx context.Context, key string, value []byte, rev int64) (WriteResult, error)
+	// Delete deletes key if its ModRevision equals rev.
+	Delete(ctx context.Context, key string, rev int64) (WriteResult, error)
+	// Watch returns the events of all keys with the given prefix after revision rev. The channel is
+	// closed once ctx is done or the Backend is closed.
+	Watch(ctx context.Context, prefix string, rev int64) (<-chan Event, error)
+	// RequestProgress sends a progress event with the current revision to all watches.
+	RequestProgress(ctx context.Context) error
+	// CurrentRevision returns the current revision.
+	CurrentRevision(ctx context.Context) (int64, error)
+	// CompactRevision returns the revision of the last compaction, or 0 if the Backend was never compacted.
+	CompactRevision() int64
+	// Compact discards the history before revision rev. Lists and watches at older revisions fail
+	// with ErrCompacted afterwards.
+	Compact(ctx context.Context, rev int64) error
+	// Close releases all resources of the Backend.
+	Close() error
+}
+
+// BackendFactory opens a Backend that persists its data at path, e.g. a database file.
+type BackendFactory func(path string) (Backend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a Backend available under name, e.g. as --storage-backend of the apiserver Builder.
+// Like database/sql drivers, backends with heavy dependencies register themselves when their package is
+// imported, so only users that select them depend on them. It panics if name is registered twice.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("storage backend %q is registered twice", name))
+	}
+	backends[name] = factory
+}
+
+// LookupBackend returns the factory of the Backend registered under name.
+func LookupBackend(name string) (BackendFactory, bool) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	factory, ok := backends[name]
+	return factory, ok
+}
+
+// RegisteredBackends returns the sorted names of the registered backends.
+func RegisteredBackends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/storage"
+	"go.opendefense.cloud/kit/apiserver/storage/memory"
+)
+
+var _ = Describe("RegisterBackend", func() {
+	It("should make backends available by their name", func() {
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+		storage.RegisterBackend("test", func(path string) (storage.Backend, error) {
+			Expect(path).To(Equal("test.db"))
+			return backend, nil
+		})
+
+		factory, ok := storage.LookupBackend("test")
+		Expect(ok).To(BeTrue())
+		Expect(factory("test.db")).To(BeIdenticalTo(backend))
+		Expect(storage.RegisteredBackends()).To(ContainElement("test"))
+		_, ok = storage.LookupBackend("unknown")
+		Expect(ok).To(BeFalse())
+
+		Expect(func() {
+			storage.RegisterBackend("test", nil)
+		}).To(PanicWith(ContainSubstring("registered twice")))
+	})
+})
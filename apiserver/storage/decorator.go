@@ -0,0 +1,112 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/registry/generic"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// NewStorageDecorator returns a generic.StorageDecorator that stores resources in backend instead of etcd.
+// If cached is true, reads and watches are served from a watch cache, like for the etcd storage of the
+// generic apiserver.
+func NewStorageDecorator(backend Backend, cached bool) generic.StorageDecorator {
+	return func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc apistorage.AttrFunc,
+		triggerFuncs apistorage.IndexerFuncs,
+		indexers *cache.Indexers) (apistorage.Interface, factory.DestroyFunc, error) {
+		s := New(backend, config.Codec, newFunc, newListFunc, config.Prefix, resourcePrefix, config.GroupResource, config.Transformer)
+		if !cached {
+			return s, func() {}, nil
+		}
+
+		cacher, err := cacherstorage.NewCacherFromConfig(cacherstorage.Config{
+			Storage:             s,
+			Versioner:           apistorage.APIObjectVersioner{},
+			GroupResource:       config.GroupResource,
+			EventsHistoryWindow: config.EventsHistoryWindow,
+			ResourcePrefix:      resourcePrefix,
+			KeyFunc:             keyFunc,
+			NewFunc:             newFunc,
+			NewListFunc:         newListFunc,
+			GetAttrsFunc:        getAttrsFunc,
+			IndexerFuncs:        triggerFuncs,
+			Indexers:            indexers,
+			Codec:               config.Codec,
+		})
+		if err != nil {
+			return nil, func() {}, err
+		}
+		delegator := cacherstorage.NewCacheDelegator(cacher, s)
+		var once sync.Once
+		return delegator, func() {
+			once.Do(func() {
+				delegator.Stop()
+				cacher.Stop()
+			})
+		}, nil
+	}
+}
+
+// NewRESTOptionsGetter returns a generic.RESTOptionsGetter that stores all resources in backend,
+// encoded with codec. It allows to create registries with rest.NewStore without an apiserver,
+// e.g. in unit tests.
+func NewRESTOptionsGetter(backend Backend, codec runtime.Codec) generic.RESTOptionsGetter {
+	return &restOptionsGetter{backend: backend, codec: codec}
+}
+
+type restOptionsGetter struct {
+	backend Backend
+	codec   runtime.Codec
+}
+
+// GetRESTOptions implements generic.RESTOptionsGetter.
+func (g *restOptionsGetter) GetRESTOptions(resource schema.GroupResource, _ runtime.Object) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		StorageConfig:           storagebackend.NewDefaultConfig("/registry", g.codec).ForResource(resource),
+		Decorator:               NewStorageDecorator(g.backend, false),
+		DeleteCollectionWorkers: 1,
+		ResourcePrefix:          resource.Group + "/" + resource.Resource,
+	}, nil
+}
+
+// RunCompactor compacts backend every interval until ctx is done. Each run compacts the history up
+// to the revision observed by the previous run, so watchers always have at least interval to catch up.
+// Like etcd, an interval of 0 or less disables compaction and RunCompactor returns immediately.
+func RunCompactor(ctx context.Context, backend Backend, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	var rev int64
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if rev > 0 {
+			if err := backend.Compact(ctx, rev); err != nil {
+				klog.Errorf("failed to compact storage at revision %d: %v", rev, err)
+			}
+		}
+		current, err := backend.CurrentRevision(ctx)
+		if err != nil {
+			klog.Errorf("failed to get current storage revision: %v", err)
+			return
+		}
+		rev = current
+	}, interval)
+}
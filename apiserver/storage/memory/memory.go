@@ -0,0 +1,275 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memory provides an in-process storage.Backend that keeps all data in memory.
+// It is meant for development and unit tests, data does not survive a restart.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opendefense.cloud/kit/apiserver/storage"
+)
+
+var errClosed = errors.New("memory backend is closed")
+
+// record is a change in the history of the Backend.
+type record struct {
+	key  string
+	kv   *storage.KeyValue // nil if the key was deleted
+	prev *storage.KeyValue // nil if the key was created
+	rev  int64
+}
+
+// Backend is an in-memory storage.Backend.
+type Backend struct {
+	mu         sync.RWMutex
+	rev        int64
+	compactRev int64
+	data       map[string]*storage.KeyValue
+	// history holds all changes after compactRev in ascending order.
+	history  []record
+	watchers map[*storage.WatchQueue]struct{}
+	closed   bool
+}
+
+var _ storage.Backend = &Backend{}
+
+// New returns an empty in-memory Backend.
+func New() *Backend {
+	return &Backend{
+		// Like etcd, an empty backend is at revision 1.
+		rev:      1,
+		data:     map[string]*storage.KeyValue{},
+		watchers: map[*storage.WatchQueue]struct{}{},
+	}
+}
+
+// Get implements storage.Backend.
+func (b *Backend) Get(_ context.Context, key string) (*storage.KeyValue, int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, 0, errClosed
+	}
+	return b.data[key], b.rev, nil
+}
+
+// List implements storage.Backend.
+func (b *Backend) List(_ context.Context, prefix, startKey string, limit, rev int64) ([]*storage.KeyValue, int64, int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, 0, 0, errClosed
+	}
+	if rev == 0 {
+		rev = b.rev
+	}
+	if rev > b.rev {
+		return nil, 0, 0, storage.ErrFutureRevision
+	}
+	if rev < b.compactRev {
+		return nil, 0, 0, storage.ErrCompacted
+	}
+
+	snapshot := map[string]*storage.KeyValue{}
+	for key, kv := range b.data {
+		if strings.HasPrefix(key, prefix) && key >= startKey {
+			snapshot[key] = kv
+		}
+	}
+	// Roll back the changes after rev to get the state at rev.
+	for i := len(b.history) - 1; i >= 0 && b.history[i].rev > rev; i-- {
+		r := b.history[i]
+		if !strings.HasPrefix(r.key, prefix) || r.key < startKey {
+			continue
+		}
+		if r.prev == nil {
+			delete(snapshot, r.key)
+		} else {
+			snapshot[r.key] = r.prev
+		}
+	}
+
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	count := int64(len(keys))
+	if limit > 0 && int64(len(keys)) > limit {
+		keys = keys[:limit]
+	}
+	kvs := make([]*storage.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, snapshot[key])
+	}
+	return kvs, count, rev, nil
+}
+
+// Count implements storage.Backend.
+func (b *Backend) Count(_ context.Context, prefix string) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, errClosed
+	}
+	var count int64
+	for key := range b.data {
+		if strings.HasPrefix(key, prefix) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Put implements storage.Backend.
+func (b *Backend) Put(_ context.Context, key string, value []byte, rev int64) (storage.WriteResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return storage.WriteResult{}, errClosed
+	}
+	prev := b.data[key]
+	if !storage.Matches(prev, rev) {
+		return storage.WriteResult{Revision: b.rev, Current: prev}, nil
+	}
+	b.rev++
+	kv := &storage.KeyValue{Key: key, Value: slices.Clone(value), ModRevision: b.rev}
+	b.data[key] = kv
+	b.record(record{key: key, kv: kv, prev: prev, rev: b.rev})
+	return storage.WriteResult{Succeeded: true, Revision: b.rev}, nil
+}
+
+// Delete implements storage.Backend.
+func (b *Backend) Delete(_ context.Context, key string, rev int64) (storage.WriteResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return storage.WriteResult{}, errClosed
+	}
+	prev := b.data[key]
+	if prev == nil || !storage.Matches(prev, rev) {
+		return storage.WriteResult{Revision: b.rev, Current: prev}, nil
+	}
+	b.rev++
+	delete(b.data, key)
+	b.record(record{key: key, prev: prev, rev: b.rev})
+	return storage.WriteResult{Succeeded: true, Revision: b.rev}, nil
+}
+
+// record appends r to the history and sends it to all watchers. b.mu must be held.
+func (b *Backend) record(r record) {
+	b.history = append(b.history, r)
+	e := toEvent(r)
+	for w := range b.watchers {
+		w.Enqueue(e)
+	}
+}
+
+func toEvent(r record) storage.Event {
+	e := storage.Event{Type: storage.EventPut, Key: r.key, Revision: r.rev}
+	if r.kv == nil {
+		e.Type = storage.EventDelete
+	} else {
+		e.Value = r.kv.Value
+	}
+	if r.prev != nil {
+		e.PrevValue = r.prev.Value
+	}
+	return e
+}
+
+// Watch implements storage.Backend.
+func (b *Backend) Watch(ctx context.Context, prefix string, rev int64) (<-chan storage.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, errClosed
+	}
+	if rev < b.compactRev {
+		return nil, storage.ErrCompacted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := storage.NewWatchQueue(prefix, rev, cancel)
+	for _, r := range b.history {
+		w.Enqueue(toEvent(r))
+	}
+	b.watchers[w] = struct{}{}
+
+	go func() {
+		w.Run(ctx)
+		b.mu.Lock()
+		delete(b.watchers, w)
+		b.mu.Unlock()
+	}()
+	return w.Events(), nil
+}
+
+// RequestProgress implements storage.Backend.
+func (b *Backend) RequestProgress(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	for w := range b.watchers {
+		w.Enqueue(storage.Event{Type: storage.EventProgress, Revision: b.rev})
+	}
+	return nil
+}
+
+// CurrentRevision implements storage.Backend.
+func (b *Backend) CurrentRevision(context.Context) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, errClosed
+	}
+	return b.rev, nil
+}
+
+// CompactRevision implements storage.Backend.
+func (b *Backend) CompactRevision() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.compactRev
+}
+
+// Compact implements storage.Backend.
+func (b *Backend) Compact(_ context.Context, rev int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	if rev > b.rev {
+		return storage.ErrFutureRevision
+	}
+	if rev <= b.compactRev {
+		return nil
+	}
+	i, _ := slices.BinarySearchFunc(b.history, rev+1, func(r record, rev int64) int {
+		return cmp.Compare(r.rev, rev)
+	})
+	b.history = slices.Clone(b.history[i:])
+	b.compactRev = rev
+	return nil
+}
+
+// Close implements storage.Backend. It stops all watches.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for w := range b.watchers {
+		w.Stop()
+	}
+	return nil
+}
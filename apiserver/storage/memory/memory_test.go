@@ -0,0 +1,146 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/storage"
+)
+
+var _ = Describe("Backend", func() {
+	var (
+		ctx context.Context
+		b   *Backend
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		b = New()
+		DeferCleanup(b.Close)
+	})
+
+	put := func(key, value string, rev int64) storage.WriteResult {
+		res, err := b.Put(ctx, key, []byte(value), rev)
+		Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	keys := func(kvs []*storage.KeyValue) []string {
+		var result []string
+		for _, kv := range kvs {
+			result = append(result, kv.Key)
+		}
+		return result
+	}
+
+	It("should increment the revision on every write", func() {
+		rev, err := b.CurrentRevision(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rev).To(BeEquivalentTo(1))
+		Expect(put("/a", "1", 0)).To(Equal(storage.WriteResult{Succeeded: true, Revision: 2}))
+		Expect(put("/a", "2", 2)).To(Equal(storage.WriteResult{Succeeded: true, Revision: 3}))
+		res, err := b.Delete(ctx, "/a", 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(storage.WriteResult{Succeeded: true, Revision: 4}))
+
+		kv, rev, err := b.Get(ctx, "/a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(kv).To(BeNil())
+		Expect(rev).To(BeEquivalentTo(4))
+	})
+
+	It("should reject writes with an unexpected revision", func() {
+		put("/a", "1", 0)
+		res := put("/a", "2", 0)
+		Expect(res.Succeeded).To(BeFalse())
+		Expect(res.Current.Value).To(Equal([]byte("1")))
+
+		res, err := b.Delete(ctx, "/a", 5)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Succeeded).To(BeFalse())
+		Expect(res.Current.ModRevision).To(BeEquivalentTo(2))
+	})
+
+	It("should list keys by prefix with limit and start key", func() {
+		put("/a/1", "", 0)
+		put("/a/2", "", 0)
+		put("/a/3", "", 0)
+		put("/b/1", "", 0)
+
+		kvs, count, rev, err := b.List(ctx, "/a/", "/a/2", 1, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys(kvs)).To(Equal([]string{"/a/2"}))
+		Expect(count).To(BeEquivalentTo(2))
+		Expect(rev).To(BeEquivalentTo(5))
+	})
+
+	It("should list older revisions until they are compacted", func() {
+		put("/a", "1", 0)
+		put("/a", "2", 2)
+		put("/b", "1", 0)
+		_, err := b.Delete(ctx, "/a", 3)
+		Expect(err).ToNot(HaveOccurred())
+
+		kvs, _, _, err := b.List(ctx, "/", "", 0, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(kvs).To(HaveExactElements(&storage.KeyValue{Key: "/a", Value: []byte("1"), ModRevision: 2}))
+
+		_, _, _, err = b.List(ctx, "/", "", 0, 6)
+		Expect(err).To(MatchError(storage.ErrFutureRevision))
+
+		Expect(b.Compact(ctx, 4)).To(Succeed())
+		_, _, _, err = b.List(ctx, "/", "", 0, 3)
+		Expect(err).To(MatchError(storage.ErrCompacted))
+		kvs, _, _, err = b.List(ctx, "/", "", 0, 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys(kvs)).To(Equal([]string{"/a", "/b"}))
+	})
+
+	It("should replay history and send new events to watches", func() {
+		put("/a/1", "1", 0)
+		put("/a/2", "1", 0)
+		events, err := b.Watch(ctx, "/a/", 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		put("/b/1", "1", 0)
+		put("/a/1", "2", 2)
+
+		Eventually(events).Should(Receive(Equal(storage.Event{Type: storage.EventPut, Key: "/a/2", Value: []byte("1"), Revision: 3})))
+		Eventually(events).Should(Receive(Equal(storage.Event{Type: storage.EventPut, Key: "/a/1", Value: []byte("2"), PrevValue: []byte("1"), Revision: 5})))
+		Expect(b.RequestProgress(ctx)).To(Succeed())
+		Eventually(events).Should(Receive(Equal(storage.Event{Type: storage.EventProgress, Revision: 5})))
+	})
+
+	It("should not send events of the watched revision", func() {
+		res := put("/a", "1", 0)
+		events, err := b.Watch(ctx, "/", res.Revision)
+		Expect(err).ToNot(HaveOccurred())
+		Consistently(events).ShouldNot(Receive())
+	})
+
+	It("should close watches when the context is done or the backend is closed", func() {
+		watchCtx, cancel := context.WithCancel(ctx)
+		first, err := b.Watch(watchCtx, "/", 0)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := b.Watch(ctx, "/", 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		cancel()
+		Eventually(first).Should(BeClosed())
+		Expect(b.Close()).To(Succeed())
+		Eventually(second).Should(BeClosed())
+	})
+
+	It("should reject watches of compacted revisions", func() {
+		put("/a", "1", 0)
+		put("/a", "2", 2)
+		Expect(b.Compact(ctx, 3)).To(Succeed())
+		_, err := b.Watch(ctx, "/", 2)
+		Expect(err).To(MatchError(storage.ErrCompacted))
+	})
+})
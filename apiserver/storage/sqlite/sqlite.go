@@ -0,0 +1,454 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sqlite provides a storage.Backend that persists data in a local SQLite database file.
+// It is meant for single-node deployments that cannot run etcd. The database must only be
+// opened by a single process at a time.
+//
+// Importing the package registers the backend as "sqlite", which makes it selectable with
+// --storage-backend=sqlite. It uses the cgo SQLite driver github.com/mattn/go-sqlite3, binaries
+// that import it must be built with CGO_ENABLED=1.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 database/sql driver
+	"go.opendefense.cloud/kit/apiserver/storage"
+	"k8s.io/klog/v2"
+)
+
+// The log table holds every revision of every key, a deleted row is a tombstone. The row id is the
+// revision, AUTOINCREMENT guarantees that revisions are never reused, even after compaction.
+const schema = `
+CREATE TABLE IF NOT EXISTS log (
+	rev INTEGER PRIMARY KEY AUTOINCREMENT,
+	key TEXT NOT NULL,
+	value BLOB,
+	prev_rev INTEGER,
+	deleted INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS log_key_rev ON log (key, rev);
+CREATE TABLE IF NOT EXISTS meta (
+	name TEXT PRIMARY KEY,
+	value INTEGER NOT NULL
+);
+`
+
+// latest selects the latest row of every key in the range [?, ?) at revision ?.
+const latest = `SELECT key, value, deleted, MAX(rev) AS rev FROM log WHERE key >= ? AND key < ? AND rev <= ? GROUP BY key`
+
+var errClosed = errors.New("sqlite backend is closed")
+
+// Backend is a storage.Backend backed by a SQLite database.
+type Backend struct {
+	db *sql.DB
+
+	// mu serializes writes and guards rev and compactRev. Reads hold it for reading, so they
+	// observe the revision of the data they read.
+	mu         sync.RWMutex
+	rev        int64
+	compactRev int64
+	closed     bool
+
+	// watchMu guards the watchers and dispatched, the revision up to which events have
+	// been sent to the watchers.
+	watchMu    sync.Mutex
+	watchers   map[*storage.WatchQueue]struct{}
+	dispatched int64
+
+	notify chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+var _ storage.Backend = &Backend{}
+
+func init() {
+	storage.RegisterBackend("sqlite", func(path string) (storage.Backend, error) {
+		return New(path)
+	})
+}
+
+// New opens or creates the SQLite database at path.
+func New(path string) (*Backend, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	// A single connection serializes all access, so the database is never busy.
+	db.SetMaxOpenConns(1)
+
+	b := &Backend{
+		db:       db,
+		watchers: map[*storage.WatchQueue]struct{}{},
+		notify:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := b.init(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database %q: %w", path, err)
+	}
+	b.dispatched = b.rev
+	go b.dispatch()
+	return b, nil
+}
+
+func (b *Backend) init() error {
+	if _, err := b.db.Exec(schema); err != nil {
+		return err
+	}
+	// Like etcd, an empty database is at revision 1.
+	if _, err := b.db.Exec(`INSERT INTO sqlite_sequence (name, seq) SELECT 'log', 1 WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'log')`); err != nil {
+		return err
+	}
+	if err := b.db.QueryRow(`SELECT COALESCE((SELECT seq FROM sqlite_sequence WHERE name = 'log'), 0)`).Scan(&b.rev); err != nil {
+		return err
+	}
+	return b.db.QueryRow(`SELECT COALESCE((SELECT value FROM meta WHERE name = 'compact_rev'), 0)`).Scan(&b.compactRev)
+}
+
+// prefixEnd returns the smallest key that is greater than all keys with the given prefix.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// All keys have the prefix, use a key greater than any valid UTF-8 string.
+	return "\xff"
+}
+
+// get returns the current value of key, or nil if it does not exist.
+func get(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}, key string) (*storage.KeyValue, error) {
+	kv := &storage.KeyValue{Key: key}
+	var deleted bool
+	err := q.QueryRowContext(ctx, `SELECT value, rev, deleted FROM log WHERE key = ? ORDER BY rev DESC LIMIT 1`, key).
+		Scan(&kv.Value, &kv.ModRevision, &deleted)
+	if errors.Is(err, sql.ErrNoRows) || deleted {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kv, nil
+}
+
+// Get implements storage.Backend.
+func (b *Backend) Get(ctx context.Context, key string) (*storage.KeyValue, int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, 0, errClosed
+	}
+	kv, err := get(ctx, b.db, key)
+	return kv, b.rev, err
+}
+
+// List implements storage.Backend.
+func (b *Backend) List(ctx context.Context, prefix, startKey string, limit, rev int64) ([]*storage.KeyValue, int64, int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, 0, 0, errClosed
+	}
+	if rev == 0 {
+		rev = b.rev
+	}
+	if rev > b.rev {
+		return nil, 0, 0, storage.ErrFutureRevision
+	}
+	if rev < b.compactRev {
+		return nil, 0, 0, storage.ErrCompacted
+	}
+	start := max(prefix, startKey)
+	end := prefixEnd(prefix)
+
+	var count int64
+	if err := b.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+latest+`) WHERE deleted = 0`, start, end, rev).Scan(&count); err != nil {
+		return nil, 0, 0, err
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := b.db.QueryContext(ctx, `SELECT key, value, rev FROM (`+latest+`) WHERE deleted = 0 ORDER BY key LIMIT ?`, start, end, rev, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+	var kvs []*storage.KeyValue
+	for rows.Next() {
+		kv := &storage.KeyValue{}
+		if err := rows.Scan(&kv.Key, &kv.Value, &kv.ModRevision); err != nil {
+			return nil, 0, 0, err
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, count, rev, rows.Err()
+}
+
+// Count implements storage.Backend.
+func (b *Backend) Count(ctx context.Context, prefix string) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, errClosed
+	}
+	var count int64
+	err := b.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+latest+`) WHERE deleted = 0`, prefix, prefixEnd(prefix), b.rev).Scan(&count)
+	return count, err
+}
+
+// Put implements storage.Backend.
+func (b *Backend) Put(ctx context.Context, key string, value []byte, rev int64) (storage.WriteResult, error) {
+	if value == nil {
+		value = []byte{}
+	}
+	return b.write(ctx, key, value, rev, false)
+}
+
+// Delete implements storage.Backend.
+func (b *Backend) Delete(ctx context.Context, key string, rev int64) (storage.WriteResult, error) {
+	return b.write(ctx, key, nil, rev, true)
+}
+
+// write appends a new revision of key to the log if its current revision is rev.
+func (b *Backend) write(ctx context.Context, key string, value []byte, rev int64, deleted bool) (_ storage.WriteResult, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return storage.WriteResult{}, errClosed
+	}
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storage.WriteResult{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	current, err := get(ctx, tx, key)
+	if err != nil {
+		return storage.WriteResult{}, err
+	}
+	if (deleted && current == nil) || !storage.Matches(current, rev) {
+		return storage.WriteResult{Revision: b.rev, Current: current}, tx.Rollback()
+	}
+	var prevRev sql.NullInt64
+	if current != nil {
+		prevRev = sql.NullInt64{Int64: current.ModRevision, Valid: true}
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO log (key, value, prev_rev, deleted) VALUES (?, ?, ?, ?)`, key, value, prevRev, deleted)
+	if err != nil {
+		return storage.WriteResult{}, err
+	}
+	newRev, err := res.LastInsertId()
+	if err != nil {
+		return storage.WriteResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return storage.WriteResult{}, err
+	}
+
+	b.rev = newRev
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+	return storage.WriteResult{Succeeded: true, Revision: newRev}, nil
+}
+
+// events returns the events in the revision range (from, to] for keys with the given prefix.
+func (b *Backend) events(ctx context.Context, prefix string, from, to int64) ([]storage.Event, error) {
+	rows, err := b.db.QueryContext(ctx, `
+SELECT l.rev, l.key, l.value, l.deleted, p.value FROM log l LEFT JOIN log p ON p.rev = l.prev_rev
+WHERE l.rev > ? AND l.rev <= ? AND l.key >= ? AND l.key < ? ORDER BY l.rev`, from, to, prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []storage.Event
+	for rows.Next() {
+		e := storage.Event{Type: storage.EventPut}
+		var deleted bool
+		if err := rows.Scan(&e.Revision, &e.Key, &e.Value, &deleted, &e.PrevValue); err != nil {
+			return nil, err
+		}
+		if deleted {
+			e.Type = storage.EventDelete
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// dispatch sends the events of new revisions to the watchers until the backend is closed.
+func (b *Backend) dispatch() {
+	defer close(b.done)
+	for {
+		select {
+		case <-b.notify:
+		case <-b.stop:
+			return
+		}
+
+		b.mu.RLock()
+		b.watchMu.Lock()
+		events, err := b.events(context.Background(), "", b.dispatched, b.rev)
+		if err == nil {
+			for _, e := range events {
+				for w := range b.watchers {
+					w.Enqueue(e)
+				}
+			}
+			b.dispatched = b.rev
+		} else {
+			// Watchers must not miss any events, so stop them and let the clients watch again.
+			klog.Errorf("failed to read sqlite storage events: %v", err)
+			for w := range b.watchers {
+				w.Stop()
+			}
+		}
+		b.watchMu.Unlock()
+		b.mu.RUnlock()
+	}
+}
+
+// Watch implements storage.Backend.
+func (b *Backend) Watch(ctx context.Context, prefix string, rev int64) (<-chan storage.Event, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, errClosed
+	}
+	if rev < b.compactRev {
+		return nil, storage.ErrCompacted
+	}
+	b.watchMu.Lock()
+	defer b.watchMu.Unlock()
+
+	// Replay the events that were already dispatched, newer events are sent by dispatch. The
+	// queue drops the events up to rev, which may not have been dispatched yet.
+	events, err := b.events(ctx, prefix, rev, b.dispatched)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := storage.NewWatchQueue(prefix, rev, cancel)
+	for _, e := range events {
+		w.Enqueue(e)
+	}
+	b.watchers[w] = struct{}{}
+
+	go func() {
+		w.Run(ctx)
+		b.watchMu.Lock()
+		delete(b.watchers, w)
+		b.watchMu.Unlock()
+	}()
+	return w.Events(), nil
+}
+
+// RequestProgress implements storage.Backend.
+func (b *Backend) RequestProgress(context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return errClosed
+	}
+	b.watchMu.Lock()
+	defer b.watchMu.Unlock()
+	for w := range b.watchers {
+		w.Enqueue(storage.Event{Type: storage.EventProgress, Revision: b.dispatched})
+	}
+	return nil
+}
+
+// CurrentRevision implements storage.Backend.
+func (b *Backend) CurrentRevision(context.Context) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, errClosed
+	}
+	return b.rev, nil
+}
+
+// CompactRevision implements storage.Backend.
+func (b *Backend) CompactRevision() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.compactRev
+}
+
+// Compact implements storage.Backend. It deletes all revisions that are superseded at rev
+// and the tombstones of keys deleted at or before rev.
+func (b *Backend) Compact(ctx context.Context, rev int64) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	if rev > b.rev {
+		return storage.ErrFutureRevision
+	}
+	if rev <= b.compactRev {
+		return nil
+	}
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if _, err := tx.ExecContext(ctx, `
+DELETE FROM log WHERE rev <= ?1 AND rev < (SELECT MAX(n.rev) FROM log n WHERE n.key = log.key AND n.rev <= ?1)`, rev); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM log WHERE rev <= ? AND deleted = 1`, rev); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO meta (name, value) VALUES ('compact_rev', ?) ON CONFLICT (name) DO UPDATE SET value = excluded.value`, rev); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	b.compactRev = rev
+	return nil
+}
+
+// Close implements storage.Backend. It stops all watches and closes the database.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.stop)
+	<-b.done
+	b.watchMu.Lock()
+	for w := range b.watchers {
+		w.Stop()
+	}
+	b.watchMu.Unlock()
+	return b.db.Close()
+}
@@ -0,0 +1,16 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSQLite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SQLite Suite")
+}
@@ -0,0 +1,753 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/identity"
+	"k8s.io/klog/v2"
+)
+
+// authenticatedDataString satisfies the value.Context interface. It uses the key to
+// authenticate the stored data. This does not defend against reuse of previously
+// encrypted values under the same key, but will prevent an attacker from using an
+// encrypted value from a different key.
+type authenticatedDataString string
+
+// AuthenticatedData implements the value.Context interface.
+func (d authenticatedDataString) AuthenticatedData() []byte {
+	return []byte(d)
+}
+
+// store implements k8s.io/apiserver/pkg/storage.Interface on top of a Backend.
+// Its semantics follow the etcd3 implementation of the generic apiserver.
+type store struct {
+	backend        Backend
+	codec          runtime.Codec
+	versioner      apistorage.Versioner
+	transformer    value.Transformer
+	pathPrefix     string
+	resourcePrefix string
+	groupResource  schema.GroupResource
+	newFunc        func() runtime.Object
+	newListFunc    func() runtime.Object
+}
+
+var _ apistorage.Interface = &store{}
+
+// objState is the decoded state of a stored object.
+type objState struct {
+	obj   runtime.Object
+	meta  *apistorage.ResponseMeta
+	rev   int64
+	data  []byte
+	stale bool
+}
+
+// New returns a storage.Interface that stores the objects of a single resource in backend.
+// Keys are stored below prefix. If transformer is nil, data is stored untransformed.
+func New(
+	backend Backend,
+	codec runtime.Codec,
+	newFunc, newListFunc func() runtime.Object,
+	prefix, resourcePrefix string,
+	groupResource schema.GroupResource,
+	transformer value.Transformer,
+) apistorage.Interface {
+	if transformer == nil {
+		transformer = identity.NewEncryptCheckTransformer()
+	}
+	pathPrefix := path.Join("/", prefix)
+	if !strings.HasSuffix(pathPrefix, "/") {
+		// Ensure the pathPrefix ends in "/" here to simplify key concatenation later.
+		pathPrefix += "/"
+	}
+	return &store{
+		backend:        backend,
+		codec:          codec,
+		versioner:      apistorage.APIObjectVersioner{},
+		transformer:    transformer,
+		pathPrefix:     pathPrefix,
+		resourcePrefix: resourcePrefix,
+		groupResource:  groupResource,
+		newFunc:        newFunc,
+		newListFunc:    newListFunc,
+	}
+}
+
+// Versioner implements storage.Interface.
+func (s *store) Versioner() apistorage.Versioner {
+	return s.versioner
+}
+
+// Create implements storage.Interface.
+func (s *store) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	preparedKey, err := s.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	if version, err := s.versioner.ObjectResourceVersion(obj); err == nil && version != 0 {
+		return apistorage.ErrResourceVersionSetOnCreate
+	}
+	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
+		return fmt.Errorf("PrepareObjectForStorage failed: %v", err)
+	}
+	data, err := runtime.Encode(s.codec, obj)
+	if err != nil {
+		return err
+	}
+	newData, err := s.transformer.TransformToStorage(ctx, data, authenticatedDataString(preparedKey))
+	if err != nil {
+		return apistorage.NewInternalError(err)
+	}
+
+	res, err := s.backend.Put(ctx, preparedKey, newData, 0)
+	if err != nil {
+		return err
+	}
+	if !res.Succeeded {
+		return apistorage.NewKeyExistsError(preparedKey, 0)
+	}
+
+	if out != nil {
+		return s.decode(data, out, res.Revision)
+	}
+	return nil
+}
+
+// Delete implements storage.Interface.
+func (s *store) Delete(
+	ctx context.Context, key string, out runtime.Object, preconditions *apistorage.Preconditions,
+	validateDeletion apistorage.ValidateObjectFunc, cachedExistingObject runtime.Object, opts apistorage.DeleteOptions) error {
+	preparedKey, err := s.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	v, err := conversion.EnforcePtr(out)
+	if err != nil {
+		return fmt.Errorf("unable to convert output object to pointer: %v", err)
+	}
+
+	getCurrentState := s.getCurrentState(ctx, preparedKey, v, false)
+
+	var origState *objState
+	var origStateIsCurrent bool
+	if cachedExistingObject != nil {
+		origState, err = s.getStateFromObject(cachedExistingObject)
+	} else {
+		origState, err = getCurrentState()
+		origStateIsCurrent = true
+	}
+	if err != nil {
+		return err
+	}
+
+	for {
+		if preconditions != nil {
+			if err := preconditions.Check(preparedKey, origState.obj); err != nil {
+				if origStateIsCurrent {
+					return err
+				}
+
+				// It's possible we're working with stale data.
+				cachedRev := origState.rev
+				cachedUpdateErr := err
+				origState, err = getCurrentState()
+				if err != nil {
+					return err
+				}
+				origStateIsCurrent = true
+				if cachedRev == origState.rev {
+					return cachedUpdateErr
+				}
+				continue
+			}
+		}
+		if err := validateDeletion(ctx, origState.obj); err != nil {
+			if origStateIsCurrent {
+				return err
+			}
+
+			// It's possible we're working with stale data.
+			cachedRev := origState.rev
+			cachedUpdateErr := err
+			origState, err = getCurrentState()
+			if err != nil {
+				return err
+			}
+			origStateIsCurrent = true
+			if cachedRev == origState.rev {
+				return cachedUpdateErr
+			}
+			continue
+		}
+
+		res, err := s.backend.Delete(ctx, preparedKey, origState.rev)
+		if err != nil {
+			return err
+		}
+		if !res.Succeeded {
+			klog.V(4).Infof("deletion of %s failed because of a conflict, going to retry", preparedKey)
+			origState, err = s.getState(ctx, res.Current, preparedKey, v, false)
+			if err != nil {
+				return err
+			}
+			origStateIsCurrent = true
+			continue
+		}
+		return s.decode(origState.data, out, res.Revision)
+	}
+}
+
+// Watch implements storage.Interface.
+func (s *store) Watch(ctx context.Context, key string, opts apistorage.ListOptions) (watch.Interface, error) {
+	preparedKey, err := s.prepareKey(key)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.versioner.ParseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.watch(ctx, preparedKey, int64(rev), opts)
+}
+
+// Get implements storage.Interface.
+func (s *store) Get(ctx context.Context, key string, opts apistorage.GetOptions, out runtime.Object) error {
+	preparedKey, err := s.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	kv, rev, err := s.backend.Get(ctx, preparedKey)
+	if err != nil {
+		return err
+	}
+	if err := s.validateMinimumResourceVersion(opts.ResourceVersion, uint64(rev)); err != nil {
+		return err
+	}
+
+	if kv == nil {
+		if opts.IgnoreNotFound {
+			return runtime.SetZeroValue(out)
+		}
+		return apistorage.NewKeyNotFoundError(preparedKey, 0)
+	}
+
+	data, _, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(preparedKey))
+	if err != nil {
+		return apistorage.NewInternalError(err)
+	}
+	return s.decode(data, out, kv.ModRevision)
+}
+
+// GetList implements storage.Interface.
+func (s *store) GetList(ctx context.Context, key string, opts apistorage.ListOptions, listObj runtime.Object) error {
+	keyPrefix, err := s.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	listPtr, err := meta.GetItemsPtr(listObj)
+	if err != nil {
+		return err
+	}
+	v, err := conversion.EnforcePtr(listPtr)
+	if err != nil || v.Kind() != reflect.Slice {
+		return fmt.Errorf("need ptr to slice: %v", err)
+	}
+
+	// For recursive lists, we need to make sure the key ended with "/" so that we only
+	// get children "directories". e.g. if we have key "/a", "/a/b", "/ab", getting keys
+	// with prefix "/a" will return all three, while with prefix "/a/" will return only
+	// "/a/b" which is the correct answer.
+	if opts.Recursive && !strings.HasSuffix(keyPrefix, "/") {
+		keyPrefix += "/"
+	}
+
+	limit := opts.Predicate.Limit
+	paging := opts.Predicate.Limit > 0
+	newItemFunc := getNewItemFunc(listObj, v)
+
+	withRev, continueKey, err := apistorage.ValidateListOptions(keyPrefix, s.versioner, opts)
+	if err != nil {
+		return err
+	}
+
+	// loop until we have filled the requested limit from the backend or there are no more results
+	var lastKey string
+	var hasMore bool
+	var kvs []*KeyValue
+	var count int64
+	for {
+		var rev int64
+		kvs, count, rev, err = s.getList(ctx, keyPrefix, continueKey, opts.Recursive, limit, withRev)
+		if err != nil {
+			if errors.Is(err, ErrFutureRevision) {
+				currentRV, getRVErr := s.backend.CurrentRevision(ctx)
+				if getRVErr != nil {
+					currentRV = 0
+				}
+				return apistorage.NewTooLargeResourceVersionError(uint64(withRev), uint64(currentRV), 0)
+			}
+			return interpretListError(err, len(opts.Predicate.Continue) > 0, continueKey, keyPrefix)
+		}
+		if err = s.validateMinimumResourceVersion(opts.ResourceVersion, uint64(rev)); err != nil {
+			return err
+		}
+		hasMore = int64(len(kvs)) < count
+
+		// indicate to the client which resource version was returned, and use the same resource version for subsequent requests.
+		if withRev == 0 {
+			withRev = rev
+		}
+
+		// take items from the response until the bucket is full, filtering as we go
+		for _, kv := range kvs {
+			if paging && int64(v.Len()) >= opts.Predicate.Limit {
+				hasMore = true
+				break
+			}
+			lastKey = kv.Key
+
+			data, _, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(kv.Key))
+			if err != nil {
+				return apistorage.NewInternalError(fmt.Errorf("unable to transform key %q: %w", kv.Key, err))
+			}
+
+			// Check if the request has already timed out before decode object
+			select {
+			case <-ctx.Done():
+				return apistorage.NewTimeoutError(kv.Key, "request did not complete within requested timeout")
+			default:
+			}
+
+			obj, _, err := s.codec.Decode(data, nil, newItemFunc())
+			if err != nil {
+				return err
+			}
+			if err := s.versioner.UpdateObject(obj, uint64(kv.ModRevision)); err != nil {
+				klog.Errorf("failed to update object version: %v", err)
+			}
+
+			if matched, err := opts.Predicate.Matches(obj); err == nil && matched {
+				v.Set(reflect.Append(v, reflect.ValueOf(obj).Elem()))
+			}
+		}
+		continueKey = lastKey + "\x00"
+
+		// no more results remain or we didn't request paging
+		if !hasMore || !paging {
+			break
+		}
+		// we're paging but we have filled our bucket
+		if int64(v.Len()) >= opts.Predicate.Limit {
+			break
+		}
+
+		if limit < maxLimit {
+			// We got incomplete result due to field/label selector dropping the object.
+			// Double page size to reduce total number of calls to the backend.
+			limit *= 2
+			if limit > maxLimit {
+				limit = maxLimit
+			}
+		}
+	}
+
+	if v.IsNil() {
+		// Ensure that we never return a nil Items pointer in the result for consistency.
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	}
+
+	continueValue, remainingItemCount, err := apistorage.PrepareContinueToken(lastKey, keyPrefix, withRev, count, hasMore, opts)
+	if err != nil {
+		return err
+	}
+	return s.versioner.UpdateList(listObj, uint64(withRev), continueValue, remainingItemCount)
+}
+
+// maxLimit is the maximum page size requested from the backend when results are filtered.
+const maxLimit = 10000
+
+func (s *store) getList(ctx context.Context, keyPrefix, startKey string, recursive bool, limit, rev int64) ([]*KeyValue, int64, int64, error) {
+	if recursive {
+		return s.backend.List(ctx, keyPrefix, startKey, limit, rev)
+	}
+	// A non-recursive list returns the single object stored at keyPrefix.
+	kvs, _, listRev, err := s.backend.List(ctx, keyPrefix, startKey, 0, rev)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for _, kv := range kvs {
+		if kv.Key == keyPrefix {
+			return []*KeyValue{kv}, 1, listRev, nil
+		}
+	}
+	return nil, 0, listRev, nil
+}
+
+// GuaranteedUpdate implements storage.Interface.
+func (s *store) GuaranteedUpdate(
+	ctx context.Context, key string, destination runtime.Object, ignoreNotFound bool,
+	preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	preparedKey, err := s.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	v, err := conversion.EnforcePtr(destination)
+	if err != nil {
+		return fmt.Errorf("unable to convert output object to pointer: %v", err)
+	}
+
+	getCurrentState := s.getCurrentState(ctx, preparedKey, v, ignoreNotFound)
+
+	var origState *objState
+	var origStateIsCurrent bool
+	if cachedExistingObject != nil {
+		origState, err = s.getStateFromObject(cachedExistingObject)
+	} else {
+		origState, err = getCurrentState()
+		origStateIsCurrent = true
+	}
+	if err != nil {
+		return err
+	}
+
+	transformContext := authenticatedDataString(preparedKey)
+	for {
+		if err := preconditions.Check(preparedKey, origState.obj); err != nil {
+			// If our data is already up to date, return the error
+			if origStateIsCurrent {
+				return err
+			}
+
+			// It's possible we were working with stale data
+			origState, err = getCurrentState()
+			if err != nil {
+				return err
+			}
+			origStateIsCurrent = true
+			continue
+		}
+
+		ret, err := s.updateState(origState, tryUpdate)
+		if err != nil {
+			// If our data is already up to date, return the error
+			if origStateIsCurrent {
+				return err
+			}
+
+			// It's possible we were working with stale data
+			cachedRev := origState.rev
+			cachedUpdateErr := err
+			origState, err = getCurrentState()
+			if err != nil {
+				return err
+			}
+			origStateIsCurrent = true
+			if cachedRev == origState.rev {
+				return cachedUpdateErr
+			}
+			continue
+		}
+
+		data, err := runtime.Encode(s.codec, ret)
+		if err != nil {
+			return err
+		}
+		if !origState.stale && bytes.Equal(data, origState.data) {
+			// if we skipped the original Get in this loop, we must refresh from
+			// the backend in order to be sure the data in the store is equivalent to
+			// our desired serialization
+			if !origStateIsCurrent {
+				origState, err = getCurrentState()
+				if err != nil {
+					return err
+				}
+				origStateIsCurrent = true
+				if !bytes.Equal(data, origState.data) {
+					// original data changed, restart loop
+					continue
+				}
+			}
+			// recheck that the data from the backend is not stale before short-circuiting a write
+			if !origState.stale {
+				return s.decode(origState.data, destination, origState.rev)
+			}
+		}
+
+		newData, err := s.transformer.TransformToStorage(ctx, data, transformContext)
+		if err != nil {
+			return apistorage.NewInternalError(err)
+		}
+
+		res, err := s.backend.Put(ctx, preparedKey, newData, origState.rev)
+		if err != nil {
+			return err
+		}
+		if !res.Succeeded {
+			klog.V(4).Infof("GuaranteedUpdate of %s failed because of a conflict, going to retry", preparedKey)
+			origState, err = s.getState(ctx, res.Current, preparedKey, v, ignoreNotFound)
+			if err != nil {
+				return err
+			}
+			origStateIsCurrent = true
+			continue
+		}
+		return s.decode(data, destination, res.Revision)
+	}
+}
+
+// Stats implements storage.Interface.
+func (s *store) Stats(ctx context.Context) (apistorage.Stats, error) {
+	prefix, err := s.prepareKey(s.resourcePrefix)
+	if err != nil {
+		return apistorage.Stats{}, err
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	count, err := s.backend.Count(ctx, prefix)
+	if err != nil {
+		return apistorage.Stats{}, err
+	}
+	return apistorage.Stats{ObjectCount: count}, nil
+}
+
+// ReadinessCheck implements storage.Interface.
+func (s *store) ReadinessCheck() error {
+	return nil
+}
+
+// RequestWatchProgress implements storage.Interface.
+func (s *store) RequestWatchProgress(ctx context.Context) error {
+	return s.backend.RequestProgress(ctx)
+}
+
+// GetCurrentResourceVersion implements storage.Interface.
+func (s *store) GetCurrentResourceVersion(ctx context.Context) (uint64, error) {
+	rev, err := s.backend.CurrentRevision(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if rev == 0 {
+		return 0, fmt.Errorf("the current resource version must be greater than 0")
+	}
+	return uint64(rev), nil
+}
+
+// SetKeysFunc implements storage.Interface. Object counts are read from the backend directly,
+// so the keys func is not used.
+func (s *store) SetKeysFunc(apistorage.KeysFunc) {}
+
+// CompactRevision implements storage.Interface.
+func (s *store) CompactRevision() int64 {
+	return s.backend.CompactRevision()
+}
+
+func (s *store) getCurrentState(ctx context.Context, key string, v reflect.Value, ignoreNotFound bool) func() (*objState, error) {
+	return func() (*objState, error) {
+		kv, _, err := s.backend.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return s.getState(ctx, kv, key, v, ignoreNotFound)
+	}
+}
+
+func (s *store) getState(ctx context.Context, kv *KeyValue, key string, v reflect.Value, ignoreNotFound bool) (*objState, error) {
+	state := &objState{
+		meta: &apistorage.ResponseMeta{},
+	}
+
+	if u, ok := v.Addr().Interface().(runtime.Unstructured); ok {
+		state.obj = u.NewEmptyInstance()
+	} else {
+		state.obj = reflect.New(v.Type()).Interface().(runtime.Object)
+	}
+
+	if kv == nil {
+		if !ignoreNotFound {
+			return nil, apistorage.NewKeyNotFoundError(key, 0)
+		}
+		if err := runtime.SetZeroValue(state.obj); err != nil {
+			return nil, err
+		}
+		return state, nil
+	}
+
+	state.rev = kv.ModRevision
+	state.meta.ResourceVersion = uint64(state.rev)
+	data, stale, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(key))
+	if err != nil {
+		return nil, apistorage.NewInternalError(err)
+	}
+	state.data = data
+	state.stale = stale
+	if err := s.decode(state.data, state.obj, state.rev); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *store) getStateFromObject(obj runtime.Object) (*objState, error) {
+	state := &objState{
+		obj:  obj,
+		meta: &apistorage.ResponseMeta{},
+	}
+
+	rv, err := s.versioner.ObjectResourceVersion(obj)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get resource version: %v", err)
+	}
+	state.rev = int64(rv)
+	state.meta.ResourceVersion = uint64(state.rev)
+
+	// Compute the serialized form - for that we need to temporarily clean
+	// its resource version field (those are not stored in the backend).
+	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
+		return nil, fmt.Errorf("PrepareObjectForStorage failed: %v", err)
+	}
+	state.data, err = runtime.Encode(s.codec, obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.versioner.UpdateObject(state.obj, rv); err != nil {
+		klog.Errorf("failed to update object version: %v", err)
+	}
+	return state, nil
+}
+
+func (s *store) updateState(st *objState, userUpdate apistorage.UpdateFunc) (runtime.Object, error) {
+	// TTLs are not supported by backends, the returned ttl is ignored.
+	ret, _, err := userUpdate(st.obj, *st.meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.versioner.PrepareObjectForStorage(ret); err != nil {
+		return nil, fmt.Errorf("PrepareObjectForStorage failed: %v", err)
+	}
+	return ret, nil
+}
+
+// decode decodes data into out and sets its resourceVersion to rev.
+func (s *store) decode(data []byte, out runtime.Object, rev int64) error {
+	if _, err := conversion.EnforcePtr(out); err != nil {
+		return fmt.Errorf("unable to convert output object to pointer: %v", err)
+	}
+	if _, _, err := s.codec.Decode(data, nil, out); err != nil {
+		return err
+	}
+	if err := s.versioner.UpdateObject(out, uint64(rev)); err != nil {
+		klog.Errorf("failed to update object version: %v", err)
+	}
+	return nil
+}
+
+// validateMinimumResourceVersion returns a 'too large resource' version error when the provided minimumResourceVersion is
+// greater than the most recent actualRevision available from storage.
+func (s *store) validateMinimumResourceVersion(minimumResourceVersion string, actualRevision uint64) error {
+	if minimumResourceVersion == "" {
+		return nil
+	}
+	minimumRV, err := s.versioner.ParseResourceVersion(minimumResourceVersion)
+	if err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("invalid resource version: %v", err))
+	}
+	if minimumRV > actualRevision {
+		return apistorage.NewTooLargeResourceVersionError(minimumRV, actualRevision, 0)
+	}
+	return nil
+}
+
+func (s *store) prepareKey(key string) (string, error) {
+	if key == ".." ||
+		strings.HasPrefix(key, "../") ||
+		strings.HasSuffix(key, "/..") ||
+		strings.Contains(key, "/../") {
+		return "", fmt.Errorf("invalid key: %q", key)
+	}
+	if key == "." ||
+		strings.HasPrefix(key, "./") ||
+		strings.HasSuffix(key, "/.") ||
+		strings.Contains(key, "/./") {
+		return "", fmt.Errorf("invalid key: %q", key)
+	}
+	if key == "" || key == "/" {
+		return "", fmt.Errorf("empty key: %q", key)
+	}
+	// We ensured that pathPrefix ends in '/' in construction, so skip any leading '/' in the key now.
+	startIndex := 0
+	if key[0] == '/' {
+		startIndex = 1
+	}
+	return s.pathPrefix + key[startIndex:], nil
+}
+
+func getNewItemFunc(listObj runtime.Object, v reflect.Value) func() runtime.Object {
+	// For unstructured lists with a target group/version, preserve the group/version in the instantiated list items
+	if unstructuredList, isUnstructured := listObj.(*unstructured.UnstructuredList); isUnstructured {
+		if apiVersion := unstructuredList.GetAPIVersion(); len(apiVersion) > 0 {
+			return func() runtime.Object {
+				return &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": apiVersion}}
+			}
+		}
+	}
+
+	// Otherwise just instantiate an empty item
+	elem := v.Type().Elem()
+	return func() runtime.Object {
+		return reflect.New(elem).Interface().(runtime.Object)
+	}
+}
+
+const (
+	expired         = "The resourceVersion for the provided list is too old."
+	continueExpired = "The provided continue parameter is too old " +
+		"to display a consistent list result. You can start a new list without " +
+		"the continue parameter."
+	inconsistentContinue = "The provided continue parameter is too old " +
+		"to display a consistent list result. You can start a new list without " +
+		"the continue parameter, or use the continue token in this response to " +
+		"retrieve the remainder of the results. Continuing with the provided " +
+		"token results in an inconsistent list - objects that were created, " +
+		"modified, or deleted between the time the first chunk was returned " +
+		"and now may show up in the list."
+)
+
+// interpretListError converts a compaction error of the backend into an expired error.
+func interpretListError(err error, paging bool, continueKey, keyPrefix string) error {
+	if !errors.Is(err, ErrCompacted) {
+		return err
+	}
+	if !paging {
+		return apierrors.NewResourceExpired(expired)
+	}
+	// continueToken.ResoureVersion=-1 means that the apiserver can
+	// continue the list at the latest resource version.
+	newToken, err := apistorage.EncodeContinue(continueKey, keyPrefix, -1)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return apierrors.NewResourceExpired(continueExpired)
+	}
+	statusError := apierrors.NewResourceExpired(inconsistentContinue)
+	statusError.ErrStatus.ListMeta.Continue = newToken
+	return statusError
+}
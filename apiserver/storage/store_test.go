@@ -0,0 +1,369 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/apis/example"
+	examplev1 "k8s.io/apiserver/pkg/apis/example/v1"
+	apistorage "k8s.io/apiserver/pkg/storage"
+
+	"go.opendefense.cloud/kit/apiserver/storage"
+	"go.opendefense.cloud/kit/apiserver/storage/memory"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	utilruntime.Must(metav1.AddMetaToScheme(scheme))
+	metav1.AddToGroupVersion(scheme, examplev1.SchemeGroupVersion)
+	utilruntime.Must(example.AddToScheme(scheme))
+	utilruntime.Must(examplev1.AddToScheme(scheme))
+}
+
+func getAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	pod := obj.(*example.Pod)
+	return pod.Labels, fields.Set{
+		"metadata.name": pod.Name,
+		"spec.nodeName": pod.Spec.NodeName,
+	}, nil
+}
+
+func newPod(name string, lbls map[string]string) *example.Pod {
+	return &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: lbls}}
+}
+
+func podKey(name string) string {
+	return "/pods/default/" + name
+}
+
+func predicate(label, field string) apistorage.SelectionPredicate {
+	labelSelector, err := labels.Parse(label)
+	utilruntime.Must(err)
+	fieldSelector, err := fields.ParseSelector(field)
+	utilruntime.Must(err)
+	return apistorage.SelectionPredicate{Label: labelSelector, Field: fieldSelector, GetAttrs: getAttrs}
+}
+
+var _ = Describe("store", func() {
+	var (
+		ctx     context.Context
+		backend *memory.Backend
+		s       apistorage.Interface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = memory.New()
+		DeferCleanup(backend.Close)
+		s = storage.New(backend, codecs.LegacyCodec(examplev1.SchemeGroupVersion),
+			func() runtime.Object { return &example.Pod{} },
+			func() runtime.Object { return &example.PodList{} },
+			"/registry", "/pods", example.Resource("pods"), nil)
+	})
+
+	create := func(pod *example.Pod) *example.Pod {
+		out := &example.Pod{}
+		Expect(s.Create(ctx, podKey(pod.Name), pod, out, 0)).To(Succeed())
+		return out
+	}
+
+	list := func(opts apistorage.ListOptions) *example.PodList {
+		opts.Recursive = true
+		if opts.Predicate.Label == nil {
+			opts.Predicate = apistorage.Everything
+		}
+		out := &example.PodList{}
+		Expect(s.GetList(ctx, "/pods/default", opts, out)).To(Succeed())
+		return out
+	}
+
+	names := func(l *example.PodList) []string {
+		var result []string
+		for _, pod := range l.Items {
+			result = append(result, pod.Name)
+		}
+		return result
+	}
+
+	It("should create and get objects with increasing resource versions", func() {
+		a := create(newPod("a", nil))
+		b := create(newPod("b", nil))
+		Expect(a.ResourceVersion).To(Equal("2"))
+		Expect(b.ResourceVersion).To(Equal("3"))
+
+		got := &example.Pod{}
+		Expect(s.Get(ctx, podKey("a"), apistorage.GetOptions{}, got)).To(Succeed())
+		Expect(got.Name).To(Equal("a"))
+		Expect(got.ResourceVersion).To(Equal("2"))
+	})
+
+	It("should reject duplicate creates and resource versions on create", func() {
+		create(newPod("a", nil))
+		err := s.Create(ctx, podKey("a"), newPod("a", nil), nil, 0)
+		Expect(apistorage.IsExist(err)).To(BeTrue())
+
+		pod := newPod("b", nil)
+		pod.ResourceVersion = "5"
+		Expect(s.Create(ctx, podKey("b"), pod, nil, 0)).To(MatchError(apistorage.ErrResourceVersionSetOnCreate))
+	})
+
+	It("should return not found errors", func() {
+		err := s.Get(ctx, podKey("missing"), apistorage.GetOptions{}, &example.Pod{})
+		Expect(apistorage.IsNotFound(err)).To(BeTrue())
+		Expect(s.Get(ctx, podKey("missing"), apistorage.GetOptions{IgnoreNotFound: true}, &example.Pod{})).To(Succeed())
+	})
+
+	It("should update objects and check preconditions", func() {
+		create(newPod("a", nil))
+		out := &example.Pod{}
+		Expect(s.GuaranteedUpdate(ctx, podKey("a"), out, false, nil, func(input runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			pod := input.(*example.Pod)
+			pod.Spec.NodeName = "node"
+			return pod, nil, nil
+		}, nil)).To(Succeed())
+		Expect(out.Spec.NodeName).To(Equal("node"))
+		Expect(out.ResourceVersion).To(Equal("3"))
+
+		uid := out.UID + "-other"
+		err := s.GuaranteedUpdate(ctx, podKey("a"), &example.Pod{}, false, &apistorage.Preconditions{UID: &uid}, func(input runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			return input, nil, nil
+		}, nil)
+		Expect(apistorage.IsInvalidObj(err)).To(BeTrue())
+	})
+
+	It("should retry updates of stale cached objects", func() {
+		stale := create(newPod("a", nil))
+		Expect(s.GuaranteedUpdate(ctx, podKey("a"), &example.Pod{}, false, nil, func(input runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			pod := input.(*example.Pod)
+			pod.Labels = map[string]string{"first": "true"}
+			return pod, nil, nil
+		}, nil)).To(Succeed())
+
+		out := &example.Pod{}
+		Expect(s.GuaranteedUpdate(ctx, podKey("a"), out, false, nil, func(input runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			pod := input.(*example.Pod)
+			pod.Spec.NodeName = "node"
+			return pod, nil, nil
+		}, stale.DeepCopy())).To(Succeed())
+		Expect(out.Labels).To(HaveKeyWithValue("first", "true"))
+		Expect(out.Spec.NodeName).To(Equal("node"))
+	})
+
+	It("should delete objects", func() {
+		create(newPod("a", nil))
+		out := &example.Pod{}
+		Expect(s.Delete(ctx, podKey("a"), out, nil, apistorage.ValidateAllObjectFunc, nil, apistorage.DeleteOptions{})).To(Succeed())
+		Expect(out.Name).To(Equal("a"))
+		Expect(out.ResourceVersion).To(Equal("3"))
+		err := s.Get(ctx, podKey("a"), apistorage.GetOptions{}, &example.Pod{})
+		Expect(apistorage.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should filter lists by label and field selectors", func() {
+		create(newPod("a", map[string]string{"app": "x"}))
+		b := newPod("b", map[string]string{"app": "y"})
+		b.Spec.NodeName = "node"
+		create(b)
+
+		Expect(names(list(apistorage.ListOptions{}))).To(Equal([]string{"a", "b"}))
+		Expect(names(list(apistorage.ListOptions{Predicate: predicate("app=x", "")}))).To(Equal([]string{"a"}))
+		Expect(names(list(apistorage.ListOptions{Predicate: predicate("", "spec.nodeName=node")}))).To(Equal([]string{"b"}))
+	})
+
+	It("should paginate lists at a consistent resource version", func() {
+		for _, name := range []string{"a", "b", "c"} {
+			create(newPod(name, nil))
+		}
+		pred := predicate("", "")
+		pred.Limit = 2
+		first := list(apistorage.ListOptions{Predicate: pred})
+		Expect(names(first)).To(Equal([]string{"a", "b"}))
+		Expect(first.Continue).ToNot(BeEmpty())
+		Expect(*first.RemainingItemCount).To(BeEquivalentTo(1))
+
+		// Changes after the first page are not visible in later pages.
+		create(newPod("d", nil))
+		pred.Continue = first.Continue
+		second := list(apistorage.ListOptions{Predicate: pred})
+		Expect(names(second)).To(Equal([]string{"c"}))
+		Expect(second.Continue).To(BeEmpty())
+		Expect(second.ResourceVersion).To(Equal(first.ResourceVersion))
+	})
+
+	It("should list at exact resource versions until they are compacted", func() {
+		create(newPod("a", nil))
+		create(newPod("b", nil))
+		Expect(s.Delete(ctx, podKey("a"), &example.Pod{}, nil, apistorage.ValidateAllObjectFunc, nil, apistorage.DeleteOptions{})).To(Succeed())
+
+		old := list(apistorage.ListOptions{ResourceVersion: "3", ResourceVersionMatch: metav1.ResourceVersionMatchExact})
+		Expect(names(old)).To(Equal([]string{"a", "b"}))
+		Expect(old.ResourceVersion).To(Equal("3"))
+
+		Expect(backend.Compact(ctx, 4)).To(Succeed())
+		err := s.GetList(ctx, "/pods/default", apistorage.ListOptions{
+			ResourceVersion: "3", ResourceVersionMatch: metav1.ResourceVersionMatchExact, Recursive: true, Predicate: apistorage.Everything,
+		}, &example.PodList{})
+		Expect(apierrors.IsResourceExpired(err)).To(BeTrue())
+	})
+
+	It("should reject resource versions newer than the current one", func() {
+		create(newPod("a", nil))
+		err := s.Get(ctx, podKey("a"), apistorage.GetOptions{ResourceVersion: "10"}, &example.Pod{})
+		Expect(apistorage.IsTooLargeResourceVersion(err)).To(BeTrue())
+	})
+
+	It("should watch changes after a resource version", func() {
+		a := create(newPod("a", map[string]string{"app": "x"}))
+		w, err := s.Watch(ctx, "/pods/default", apistorage.ListOptions{ResourceVersion: a.ResourceVersion, Recursive: true, Predicate: apistorage.Everything})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+
+		create(newPod("b", nil))
+		Expect(s.Delete(ctx, podKey("a"), &example.Pod{}, nil, apistorage.ValidateAllObjectFunc, nil, apistorage.DeleteOptions{})).To(Succeed())
+
+		Eventually(w.ResultChan()).Should(Receive(And(
+			HaveField("Type", watch.Added),
+			HaveField("Object", HaveField("ObjectMeta.Name", "b")),
+		)))
+		Eventually(w.ResultChan()).Should(Receive(And(
+			HaveField("Type", watch.Deleted),
+			HaveField("Object", HaveField("ObjectMeta.ResourceVersion", "4")),
+		)))
+	})
+
+	It("should send the current state when watching from resource version 0", func() {
+		create(newPod("a", nil))
+		w, err := s.Watch(ctx, "/pods/default", apistorage.ListOptions{ResourceVersion: "0", Recursive: true, Predicate: apistorage.Everything})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+		Eventually(w.ResultChan()).Should(Receive(HaveField("Type", watch.Added)))
+	})
+
+	It("should translate updates into events of the watched selection", func() {
+		create(newPod("a", nil))
+		w, err := s.Watch(ctx, "/pods/default", apistorage.ListOptions{ResourceVersion: "2", Recursive: true, Predicate: predicate("app=x", "")})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+
+		setLabels := func(lbls map[string]string) {
+			Expect(s.GuaranteedUpdate(ctx, podKey("a"), &example.Pod{}, false, nil, func(input runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+				pod := input.(*example.Pod)
+				pod.Labels = lbls
+				return pod, nil, nil
+			}, nil)).To(Succeed())
+		}
+		setLabels(map[string]string{"app": "x"})
+		Eventually(w.ResultChan()).Should(Receive(HaveField("Type", watch.Added)))
+		setLabels(map[string]string{"app": "x", "b": "c"})
+		Eventually(w.ResultChan()).Should(Receive(HaveField("Type", watch.Modified)))
+		setLabels(nil)
+		Eventually(w.ResultChan()).Should(Receive(HaveField("Type", watch.Deleted)))
+	})
+
+	It("should send bookmarks on progress requests to progress notify watches", func() {
+		create(newPod("a", nil))
+		w, err := s.Watch(ctx, "/pods/default", apistorage.ListOptions{ResourceVersion: "2", Recursive: true, ProgressNotify: true, Predicate: apistorage.Everything})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+
+		// The watch is established asynchronously, so request progress until it is reported.
+		Eventually(func(g Gomega) {
+			g.Expect(s.RequestWatchProgress(ctx)).To(Succeed())
+			g.Eventually(w.ResultChan()).WithTimeout(100 * time.Millisecond).Should(Receive(And(
+				HaveField("Type", watch.Bookmark),
+				HaveField("Object", HaveField("ObjectMeta.ResourceVersion", "2")),
+			)))
+		}).Should(Succeed())
+	})
+
+	It("should fail watches of compacted resource versions", func() {
+		create(newPod("a", nil))
+		create(newPod("b", nil))
+		Expect(backend.Compact(ctx, 3)).To(Succeed())
+		w, err := s.Watch(ctx, "/pods/default", apistorage.ListOptions{ResourceVersion: "2", Recursive: true, Predicate: apistorage.Everything})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(w.Stop)
+
+		var event watch.Event
+		Eventually(w.ResultChan()).Should(Receive(&event))
+		Expect(event.Type).To(Equal(watch.Error))
+		Expect(apierrors.FromObject(event.Object)).To(Satisfy(apierrors.IsResourceExpired))
+	})
+
+	It("should count objects", func() {
+		create(newPod("a", nil))
+		create(newPod("b", nil))
+		stats, err := s.Stats(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats.ObjectCount).To(BeEquivalentTo(2))
+	})
+})
+
+var _ = Describe("NewRESTOptionsGetter", func() {
+	It("should store resources in the backend", func() {
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+		getter := storage.NewRESTOptionsGetter(backend, codecs.LegacyCodec(examplev1.SchemeGroupVersion))
+
+		opts, err := getter.GetRESTOptions(example.Resource("pods"), &example.Pod{})
+		Expect(err).ToNot(HaveOccurred())
+		s, destroy, err := opts.Decorator(opts.StorageConfig, opts.ResourcePrefix, nil,
+			func() runtime.Object { return &example.Pod{} },
+			func() runtime.Object { return &example.PodList{} },
+			getAttrs, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(destroy)
+
+		Expect(s.Create(context.Background(), "/pods/a", newPod("a", nil), nil, 0)).To(Succeed())
+		count, err := backend.Count(context.Background(), "/registry/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("RunCompactor", func() {
+	It("should compact to the revision observed by the previous run", func() {
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		_, err := backend.Put(ctx, "/a", []byte("a"), 0)
+		Expect(err).ToNot(HaveOccurred())
+		go storage.RunCompactor(ctx, backend, 10*time.Millisecond)
+		Eventually(backend.CompactRevision).Should(BeEquivalentTo(2))
+	})
+
+	It("should not compact if the interval is 0", func() {
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+
+		_, err := backend.Put(context.Background(), "/a", []byte("a"), 0)
+		Expect(err).ToNot(HaveOccurred())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			storage.RunCompactor(context.Background(), backend, 0)
+		}()
+		Eventually(done).Should(BeClosed())
+		Expect(backend.CompactRevision()).To(BeEquivalentTo(0))
+	})
+})
@@ -0,0 +1,319 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/features"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	utilflowcontrol "k8s.io/apiserver/pkg/util/flowcontrol"
+	"k8s.io/klog/v2"
+)
+
+// outgoingBufSize is the buffer size of the result channel of a watch.
+const outgoingBufSize = 100
+
+// watchChan implements watch.Interface on top of a Backend watch.
+type watchChan struct {
+	store            *store
+	key              string
+	initialRev       int64
+	recursive        bool
+	progressNotify   bool
+	internalPred     apistorage.SelectionPredicate
+	ctx              context.Context
+	cancel           context.CancelFunc
+	resultChan       chan watch.Event
+	initialEventsEnd bool
+	initialEvents    bool
+}
+
+// watch watches on a key and returns a watch.Interface that transfers relevant notifications.
+// If rev is zero, it will return the existing object(s) and then start watching from
+// the revision of the returned objects.
+// If rev is non-zero, it will watch events happened after given revision.
+// If opts.Recursive is false, it watches on given key.
+// If opts.Recursive is true, it watches any children and directories under the key, excluding the root key itself.
+func (s *store) watch(ctx context.Context, key string, rev int64, opts apistorage.ListOptions) (watch.Interface, error) {
+	if opts.Recursive && !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	startWatchRV, err := s.getStartWatchResourceVersion(ctx, rev, opts)
+	if err != nil {
+		return nil, err
+	}
+	wc := &watchChan{
+		store:            s,
+		key:              key,
+		initialRev:       startWatchRV,
+		recursive:        opts.Recursive,
+		progressNotify:   opts.ProgressNotify,
+		internalPred:     opts.Predicate,
+		resultChan:       make(chan watch.Event, outgoingBufSize),
+		initialEventsEnd: isInitialEventsEndBookmarkRequired(opts),
+		initialEvents:    areInitialEventsRequired(rev, opts),
+	}
+	if opts.Predicate.Empty() {
+		// The filter doesn't filter out any object.
+		wc.internalPred = apistorage.Everything
+	}
+	wc.ctx, wc.cancel = context.WithCancel(ctx)
+	go wc.run()
+
+	// Like for etcd, there is no easy way to answer whether the watch has already caught up,
+	// so the initialization signal is delivered immediately.
+	utilflowcontrol.WatchInitialized(ctx)
+
+	return wc, nil
+}
+
+// getStartWatchResourceVersion returns the ResourceVersion the watch will be started from.
+func (s *store) getStartWatchResourceVersion(ctx context.Context, resourceVersion int64, opts apistorage.ListOptions) (int64, error) {
+	if resourceVersion > 0 {
+		return resourceVersion, nil
+	}
+	if !utilfeature.DefaultFeatureGate.Enabled(features.WatchList) {
+		return 0, nil
+	}
+	if opts.SendInitialEvents == nil || *opts.SendInitialEvents {
+		// a consistent list of the initial state will be sent first
+		return 0, nil
+	}
+	// the client is only interested in a stream of events starting at the most recent revision
+	return s.backend.CurrentRevision(ctx)
+}
+
+// isInitialEventsEndBookmarkRequired returns true if a bookmark has to be sent after the initial events.
+func isInitialEventsEndBookmarkRequired(opts apistorage.ListOptions) bool {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.WatchList) {
+		return false
+	}
+	return opts.SendInitialEvents != nil && *opts.SendInitialEvents && opts.Predicate.AllowWatchBookmarks
+}
+
+// areInitialEventsRequired returns true if all existing objects have to be sent before watching.
+func areInitialEventsRequired(resourceVersion int64, opts apistorage.ListOptions) bool {
+	if opts.SendInitialEvents == nil && resourceVersion == 0 {
+		return true // legacy case
+	}
+	if !utilfeature.DefaultFeatureGate.Enabled(features.WatchList) {
+		return false
+	}
+	return opts.SendInitialEvents != nil && *opts.SendInitialEvents
+}
+
+// Stop implements watch.Interface.
+func (wc *watchChan) Stop() {
+	wc.cancel()
+}
+
+// ResultChan implements watch.Interface.
+func (wc *watchChan) ResultChan() <-chan watch.Event {
+	return wc.resultChan
+}
+
+func (wc *watchChan) run() {
+	defer close(wc.resultChan)
+	defer wc.cancel()
+
+	if wc.initialRev > 0 && wc.initialEvents {
+		currentRev, err := wc.store.backend.CurrentRevision(wc.ctx)
+		if err != nil {
+			wc.sendError(err)
+			return
+		}
+		if wc.initialRev > currentRev {
+			wc.sendError(apistorage.NewTooLargeResourceVersionError(uint64(wc.initialRev), uint64(currentRev), 1))
+			return
+		}
+	}
+	if wc.initialEvents {
+		if !wc.sync() {
+			return
+		}
+	}
+	if wc.initialEventsEnd {
+		if !wc.sendBookmark(wc.initialRev, true) {
+			return
+		}
+	}
+
+	events, err := wc.store.backend.Watch(wc.ctx, wc.key, wc.initialRev)
+	if err != nil {
+		wc.sendError(err)
+		return
+	}
+	for {
+		select {
+		case <-wc.ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				if wc.ctx.Err() == nil {
+					wc.sendError(fmt.Errorf("watch of %q closed by the storage backend", wc.key))
+				}
+				return
+			}
+			if e.Type == EventProgress {
+				if wc.progressNotify && !wc.sendBookmark(e.Revision, false) {
+					return
+				}
+				continue
+			}
+			if !wc.recursive && e.Key != wc.key {
+				continue
+			}
+			res, err := wc.transform(e)
+			if err != nil {
+				wc.sendError(err)
+				return
+			}
+			if res != nil && !wc.send(*res) {
+				return
+			}
+		}
+	}
+}
+
+// sync sends the current state as ADDED events and sets the initial revision to the
+// revision of the state.
+func (wc *watchChan) sync() bool {
+	kvs, _, rev, err := wc.store.getList(wc.ctx, wc.key, "", wc.recursive, 0, 0)
+	if err != nil {
+		wc.sendError(err)
+		return false
+	}
+	wc.initialRev = rev
+	for _, kv := range kvs {
+		res, err := wc.transform(Event{Type: EventPut, Key: kv.Key, Value: kv.Value, Revision: kv.ModRevision})
+		if err != nil {
+			wc.sendError(err)
+			return false
+		}
+		if res != nil && !wc.send(*res) {
+			return false
+		}
+	}
+	return true
+}
+
+func (wc *watchChan) filter(obj runtime.Object) bool {
+	if wc.internalPred.Empty() {
+		return true
+	}
+	matched, err := wc.internalPred.Matches(obj)
+	return err == nil && matched
+}
+
+// transform transforms an event into a result for the user if it is not filtered.
+func (wc *watchChan) transform(e Event) (*watch.Event, error) {
+	var curObj, oldObj runtime.Object
+	var err error
+	if e.Type != EventDelete {
+		if curObj, err = wc.decode(e.Key, e.Value, e.Revision); err != nil {
+			return nil, err
+		}
+	}
+	// We need to decode the previous value only if this is a deletion event or
+	// the predicate doesn't accept all objects.
+	if len(e.PrevValue) > 0 && (e.Type == EventDelete || !wc.internalPred.Empty()) {
+		// Note that this sends the *old* object with the revision for the time at
+		// which it gets deleted.
+		if oldObj, err = wc.decode(e.Key, e.PrevValue, e.Revision); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case e.Type == EventDelete:
+		if oldObj == nil || !wc.filter(oldObj) {
+			return nil, nil
+		}
+		return &watch.Event{Type: watch.Deleted, Object: oldObj}, nil
+	case e.PrevValue == nil:
+		if !wc.filter(curObj) {
+			return nil, nil
+		}
+		return &watch.Event{Type: watch.Added, Object: curObj}, nil
+	case wc.internalPred.Empty():
+		return &watch.Event{Type: watch.Modified, Object: curObj}, nil
+	}
+
+	curObjPasses := wc.filter(curObj)
+	oldObjPasses := wc.filter(oldObj)
+	switch {
+	case curObjPasses && oldObjPasses:
+		return &watch.Event{Type: watch.Modified, Object: curObj}, nil
+	case curObjPasses && !oldObjPasses:
+		return &watch.Event{Type: watch.Added, Object: curObj}, nil
+	case !curObjPasses && oldObjPasses:
+		return &watch.Event{Type: watch.Deleted, Object: oldObj}, nil
+	}
+	return nil, nil
+}
+
+func (wc *watchChan) decode(key string, value []byte, rev int64) (runtime.Object, error) {
+	data, _, err := wc.store.transformer.TransformFromStorage(wc.ctx, value, authenticatedDataString(key))
+	if err != nil {
+		return nil, err
+	}
+	obj, err := runtime.Decode(wc.store.codec, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := wc.store.versioner.UpdateObject(obj, uint64(rev)); err != nil {
+		return nil, fmt.Errorf("failure to version api object (%d) %#v: %v", rev, obj, err)
+	}
+	return obj, nil
+}
+
+// sendBookmark sends a bookmark event at the given revision.
+func (wc *watchChan) sendBookmark(rev int64, initialEventsEnd bool) bool {
+	obj := wc.store.newFunc()
+	if err := wc.store.versioner.UpdateObject(obj, uint64(rev)); err != nil {
+		wc.sendError(fmt.Errorf("failed to propagate object resource version: %w", err))
+		return false
+	}
+	if initialEventsEnd {
+		if err := apistorage.AnnotateInitialEventsEndBookmark(obj); err != nil {
+			wc.sendError(fmt.Errorf("error while accessing object's metadata gr: %v, obj: %#v, err: %w", wc.store.groupResource, obj, err))
+			return false
+		}
+	}
+	return wc.send(watch.Event{Type: watch.Bookmark, Object: obj})
+}
+
+// sendError sends an error event. It is guaranteed to be received by the user before the result channel is closed.
+func (wc *watchChan) sendError(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if errors.Is(err, ErrCompacted) {
+		klog.V(4).Infof("watch of %q failed: %v", wc.key, err)
+		err = apierrors.NewResourceExpired("The resourceVersion for the provided watch is too old.")
+	}
+	if _, ok := err.(apierrors.APIStatus); !ok {
+		err = apierrors.NewInternalError(err)
+	}
+	status := err.(apierrors.APIStatus).Status()
+	wc.send(watch.Event{Type: watch.Error, Object: &status})
+}
+
+// send synchronously puts an event into the result channel. It returns false if the watch was stopped.
+func (wc *watchChan) send(e watch.Event) bool {
+	select {
+	case wc.resultChan <- e:
+		return true
+	case <-wc.ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Matches returns true if the current value kv has the expected revision rev, which is 0 if the
+// key must not exist. Backends use it to check the preconditions of Put and Delete.
+func Matches(kv *KeyValue, rev int64) bool {
+	if kv == nil {
+		return rev == 0
+	}
+	return kv.ModRevision == rev
+}
+
+// WatchQueue forwards the events of a Backend watch to its output channel. Events are queued
+// without limit, so writers are never blocked by slow watchers. Backends Enqueue the events of
+// all writes and Run the queue in a goroutine until the watch ends.
+type WatchQueue struct {
+	prefix string
+	rev    int64
+	cancel context.CancelFunc
+	notify chan struct{}
+	out    chan Event
+
+	mu      sync.Mutex
+	pending []Event
+}
+
+// NewWatchQueue returns a WatchQueue for the events of keys with the given prefix after revision
+// rev. cancel must cancel the context the queue is run with.
+func NewWatchQueue(prefix string, rev int64, cancel context.CancelFunc) *WatchQueue {
+	return &WatchQueue{
+		prefix: prefix,
+		rev:    rev,
+		cancel: cancel,
+		notify: make(chan struct{}, 1),
+		out:    make(chan Event),
+	}
+}
+
+// Events returns the output channel, which is closed once Run returns.
+func (q *WatchQueue) Events() <-chan Event {
+	return q.out
+}
+
+// Enqueue queues e if its key has the watched prefix and it happened after the watched revision,
+// so backends may pass events the watch already covers. Progress events are always queued.
+func (q *WatchQueue) Enqueue(e Event) {
+	if e.Type != EventProgress && (e.Revision <= q.rev || !strings.HasPrefix(e.Key, q.prefix)) {
+		return
+	}
+	q.mu.Lock()
+	q.pending = append(q.pending, e)
+	q.mu.Unlock()
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Stop ends the watch by cancelling the context of Run.
+func (q *WatchQueue) Stop() {
+	q.cancel()
+}
+
+// Run sends the queued events to the output channel until ctx is done.
+func (q *WatchQueue) Run(ctx context.Context) {
+	defer close(q.out)
+	for {
+		q.mu.Lock()
+		events := q.pending
+		q.pending = nil
+		q.mu.Unlock()
+
+		for _, e := range events {
+			select {
+			case q.out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
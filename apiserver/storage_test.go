@@ -0,0 +1,227 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+	"go.opendefense.cloud/kit/apiserver/storage/memory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/apis/example"
+	examplev1 "k8s.io/apiserver/pkg/apis/example/v1"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/apiserver/pkg/storage/cacher"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	basecompatibility "k8s.io/component-base/compatibility"
+)
+
+var _ = Describe("groupRESTOptionsGetter", func() {
+	var (
+		shared *storagebackend.ConfigForResource
+		getter *groupRESTOptionsGetter
+	)
+
+	BeforeEach(func() {
+		shared = storagebackend.NewDefaultConfig("/registry", nil).ForResource(schema.GroupResource{})
+		getter = &groupRESTOptionsGetter{
+			delegate: generic.RESTOptions{StorageConfig: shared},
+			groups: map[string]groupStorageConfig{
+				"a.example.com": {
+					prefix:          "/registry/a.example.com",
+					encodeVersioner: schema.GroupVersions{{Group: "a.example.com", Version: "v1"}},
+				},
+				"b.example.com": {
+					prefix:          "/custom",
+					encodeVersioner: schema.GroupVersions{{Group: "b.example.com", Version: "v1alpha1"}},
+					resources: map[string]resourceStorageConfig{
+						"hubs": {encodeVersioner: schema.GroupVersion{Group: "b.example.com", Version: "v1"}},
+					},
+				},
+			},
+		}
+	})
+
+	It("should apply the storage config of the resource's group", func() {
+		opts, err := getter.GetRESTOptions(schema.GroupResource{Group: "a.example.com", Resource: "foos"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig.Prefix).To(Equal("/registry/a.example.com"))
+		Expect(opts.StorageConfig.EncodeVersioner).To(Equal(runtime.GroupVersioner(schema.GroupVersions{{Group: "a.example.com", Version: "v1"}})))
+
+		opts, err = getter.GetRESTOptions(schema.GroupResource{Group: "b.example.com", Resource: "bars"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig.Prefix).To(Equal("/custom"))
+	})
+
+	It("should apply the storage config of resources stored in another version", func() {
+		opts, err := getter.GetRESTOptions(schema.GroupResource{Group: "b.example.com", Resource: "hubs"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig.Prefix).To(Equal("/custom"))
+		Expect(opts.StorageConfig.EncodeVersioner).To(Equal(runtime.GroupVersioner(schema.GroupVersion{Group: "b.example.com", Version: "v1"})))
+	})
+
+	It("should not modify the delegate's storage config", func() {
+		_, err := getter.GetRESTOptions(schema.GroupResource{Group: "a.example.com", Resource: "foos"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shared.Prefix).To(Equal("/registry"))
+	})
+
+	It("should pass through resources of unknown groups", func() {
+		opts, err := getter.GetRESTOptions(schema.GroupResource{Group: "c.example.com", Resource: "bazs"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig).To(BeIdenticalTo(shared))
+	})
+})
+
+var _ = Describe("Builder groups", func() {
+	It("should return distinct groups in registration order", func() {
+		b := NewBuilder(runtime.NewScheme()).WithGroupVersions(
+			schema.GroupVersion{Group: "b.example.com", Version: "v1"},
+			schema.GroupVersion{Group: "a.example.com", Version: "v1alpha1"},
+			schema.GroupVersion{Group: "b.example.com", Version: "v1beta1"},
+		)
+		Expect(b.groups()).To(Equal([]string{"b.example.com", "a.example.com"}))
+	})
+
+	It("should default storage prefixes below the base prefix", func() {
+		b := NewBuilder(runtime.NewScheme()).WithStoragePrefix("b.example.com", "/custom")
+		Expect(b.storagePrefix("/registry", "a.example.com")).To(Equal("/registry/a.example.com"))
+		Expect(b.storagePrefix("/registry", "b.example.com")).To(Equal("/custom"))
+	})
+})
+
+var _ = Describe("etcdServersRESTOptionsGetter", func() {
+	It("should store overridden resources on their etcd servers", func() {
+		shared := storagebackend.NewDefaultConfig("/registry", nil).ForResource(schema.GroupResource{})
+		shared.Transport.ServerList = []string{"http://etcd:2379"}
+		overrides, err := genericoptions.ParseEtcdServersOverrides([]string{"a.example.com/foos#http://etcd1:2379;http://etcd2:2379"})
+		Expect(err).ToNot(HaveOccurred())
+		getter := newEtcdServersRESTOptionsGetter(generic.RESTOptions{StorageConfig: shared}, overrides)
+
+		opts, err := getter.GetRESTOptions(schema.GroupResource{Group: "a.example.com", Resource: "foos"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig.Transport.ServerList).To(Equal([]string{"http://etcd1:2379", "http://etcd2:2379"}))
+		Expect(shared.Transport.ServerList).To(Equal([]string{"http://etcd:2379"}))
+
+		opts, err = getter.GetRESTOptions(schema.GroupResource{Group: "a.example.com", Resource: "bars"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opts.StorageConfig.Transport.ServerList).To(Equal([]string{"http://etcd:2379"}))
+	})
+})
+
+var _ = Describe("backendRESTOptionsGetter", func() {
+	var getter *backendRESTOptionsGetter
+
+	BeforeEach(func() {
+		backend := memory.New()
+		DeferCleanup(backend.Close)
+		scheme := runtime.NewScheme()
+		metav1.AddToGroupVersion(scheme, examplev1.SchemeGroupVersion)
+		utilruntime.Must(example.AddToScheme(scheme))
+		utilruntime.Must(examplev1.AddToScheme(scheme))
+		codec := serializer.NewCodecFactory(scheme).LegacyCodec(examplev1.SchemeGroupVersion)
+		getter = &backendRESTOptionsGetter{
+			delegate: generic.RESTOptions{
+				StorageConfig: storagebackend.NewDefaultConfig("/registry", codec).ForResource(schema.GroupResource{}),
+			},
+			backend:          backend,
+			enableWatchCache: true,
+			watchCacheSizes:  map[schema.GroupResource]int{{Resource: "uncached"}: 0},
+		}
+	})
+
+	decorate := func(resource string) any {
+		opts, err := getter.GetRESTOptions(schema.GroupResource{Resource: resource}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		s, destroy, err := opts.Decorator(opts.StorageConfig, resource, nil,
+			func() runtime.Object { return &example.Pod{} },
+			func() runtime.Object { return &example.PodList{} },
+			nil, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(destroy)
+		return s
+	}
+
+	It("should store resources in the backend behind the watch cache", func() {
+		Expect(decorate("cached")).To(BeAssignableToTypeOf(&cacher.CacheDelegator{}))
+	})
+
+	It("should not use the watch cache if its size is zero", func() {
+		Expect(decorate("uncached")).ToNot(BeAssignableToTypeOf(&cacher.CacheDelegator{}))
+	})
+})
+
+var _ = Describe("Builder storage backend", func() {
+	It("should default --storage-backend to the in-memory storage", func() {
+		b := NewBuilder(runtime.NewScheme()).
+			WithComponentName("test").
+			WithComponentGlobalsRegistry(basecompatibility.NewComponentGlobalsRegistry()).
+			WithInMemoryStorage()
+		b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+		b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		Expect(b.setup(flags)).To(Succeed())
+		Expect(flags.Lookup("storage-backend").Value.String()).To(Equal(StorageBackendMemory))
+		Expect(flags.Lookup("storage-backend").Usage).To(ContainSubstring("'memory'"))
+	})
+
+	It("should default --storage-backend and --sqlite-path to the SQLite storage", func() {
+		b := NewBuilder(runtime.NewScheme()).
+			WithComponentName("test").
+			WithComponentGlobalsRegistry(basecompatibility.NewComponentGlobalsRegistry()).
+			WithSQLiteStorage("/var/lib/test/test.db")
+		b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+		b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		Expect(b.setup(flags)).To(Succeed())
+		Expect(flags.Lookup("storage-backend").Value.String()).To(Equal(StorageBackendSQLite))
+		Expect(flags.Lookup("sqlite-path").Value.String()).To(Equal("/var/lib/test/test.db"))
+		// The SQLite backend is registered by importing its package, which the tests do.
+		Expect(flags.Lookup("storage-backend").Usage).To(ContainSubstring("'sqlite'"))
+	})
+
+	It("should default the storage flags to the overrides of the resource handlers", func() {
+		rh := ResourceHandler{groupResource: schema.GroupResource{Group: "a.example.com", Resource: "foos"}}
+		b := NewBuilder(runtime.NewScheme()).
+			WithComponentName("test").
+			WithComponentGlobalsRegistry(basecompatibility.NewComponentGlobalsRegistry()).
+			With(rh.WithEtcdServers("http://etcd1:2379", "http://etcd2:2379").WithWatchCacheSize(0))
+		b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+		b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		Expect(b.setup(flags)).To(Succeed())
+		Expect(flags.Parse([]string{"--etcd-servers=http://etcd:2379"})).To(Succeed())
+		Expect(b.recommendedOptions.Etcd.EtcdServersOverrides).To(Equal([]string{"a.example.com/foos#http://etcd1:2379;http://etcd2:2379"}))
+		Expect(b.recommendedOptions.Etcd.WatchCacheSizes).To(Equal([]string{"foos.a.example.com#0"}))
+		Expect(b.recommendedOptions.Etcd.Validate()).To(BeEmpty())
+	})
+
+	It("should only add the embedded etcd flags in embedded etcd mode", func() {
+		b := NewBuilder(runtime.NewScheme()).
+			WithComponentName("test").
+			WithComponentGlobalsRegistry(basecompatibility.NewComponentGlobalsRegistry())
+		b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+		b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		Expect(b.setup(flags)).To(Succeed())
+		Expect(flags.Lookup("embedded-etcd-data-dir")).To(BeNil())
+
+		b = NewBuilder(runtime.NewScheme()).
+			WithComponentName("test").
+			WithComponentGlobalsRegistry(basecompatibility.NewComponentGlobalsRegistry()).
+			WithEmbeddedEtcd(nil)
+		b.recommendedOptions = genericoptions.NewRecommendedOptions(defaultEtcdPathPrefix, nil)
+		b.recommendedOptions.SecureServing.ServerCert.CertDirectory = GinkgoT().TempDir()
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		Expect(b.setup(flags)).To(Succeed())
+		Expect(flags.Parse([]string{"--embedded-etcd-data-dir=/var/lib/etcd"})).To(Succeed())
+		Expect(b.embeddedEtcd.DataDir).To(Equal("/var/lib/etcd"))
+	})
+})
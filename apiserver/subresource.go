@@ -0,0 +1,166 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	"go.opendefense.cloud/kit/apiserver/resource"
+	"go.opendefense.cloud/kit/apiserver/rest"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	registryrest "k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// SubResourceFn returns the storage of a subresource of the resource stored by store.
+// Changes to store, e.g. to its update strategy, also apply to the resource itself.
+type SubResourceFn func(store *genericregistry.Store) rest.Storage
+
+// FieldSubResource returns a SubResourceFn of a subresource that only updates some fields of the object,
+// like status. copyFields copies these fields from one object to another. Updates of the resource itself
+// keep the fields unchanged, so they can only be changed through the subresource, e.g. an approval:
+//
+//	apiserver.Resource[*v1.Request](&v1.Request{}, v1.SchemeGroupVersion).
+//		WithSubResource("approval", apiserver.FieldSubResource[*v1.Request](func(from, to *v1.Request) {
+//			to.Spec.Approved = from.Spec.Approved
+//		}))
+func FieldSubResource[E resource.Object, T resource.ObjectWithDeepCopy[E]](copyFields func(from, to T)) SubResourceFn {
+	return func(store *genericregistry.Store) rest.Storage {
+		subStore := *store
+		subStore.UpdateStrategy = &rest.PrepareForUpdaterStrategy{
+			RESTUpdateStrategy: store.UpdateStrategy,
+			OverrideFn: func(ctx context.Context, obj, old runtime.Object) {
+				// We copy the fields to old and use it to reset everything else of the new obj
+				copyFields(obj.(T), old.(T))
+				old.(T).DeepCopyInto(obj.(E))
+			},
+		}
+
+		updateStrategy := store.UpdateStrategy
+		store.UpdateStrategy = &rest.PrepareForUpdaterStrategy{
+			RESTUpdateStrategy: updateStrategy,
+			OverrideFn: func(ctx context.Context, obj, old runtime.Object) {
+				updateStrategy.PrepareForUpdate(ctx, obj, old)
+				copyFields(old.(T), obj.(T))
+			},
+		}
+		return &fieldSubResourceStorage{store: &subStore}
+	}
+}
+
+var (
+	_ registryrest.Getter              = &fieldSubResourceStorage{}
+	_ registryrest.Updater             = &fieldSubResourceStorage{}
+	_ registryrest.ResetFieldsStrategy = &fieldSubResourceStorage{}
+)
+
+// fieldSubResourceStorage is the storage of a subresource that gets and updates the parent object, like status.
+// The store is not served directly, as its other verbs would be served on the path of the subresource, too.
+type fieldSubResourceStorage struct {
+	store *genericregistry.Store
+}
+
+// New returns an empty parent object.
+func (s *fieldSubResourceStorage) New() runtime.Object {
+	return s.store.New()
+}
+
+// Destroy does nothing, the storage is shared with and destroyed by the parent resource.
+func (s *fieldSubResourceStorage) Destroy() {}
+
+// Get retrieves the parent object.
+func (s *fieldSubResourceStorage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return s.store.Get(ctx, name, options)
+}
+
+// Update updates the parent object. Subresources never create their parent object.
+func (s *fieldSubResourceStorage) Update(ctx context.Context, name string, objInfo registryrest.UpdatedObjectInfo, createValidation registryrest.ValidateObjectFunc, updateValidation registryrest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	return s.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// GetResetFields returns the fields reset by the strategy of the store.
+func (s *fieldSubResourceStorage) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return s.store.GetResetFields()
+}
+
+// ConvertToTable converts the parent object to a table.
+func (s *fieldSubResourceStorage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return s.store.ConvertToTable(ctx, object, tableOptions)
+}
+
+// ActionFunc handles a request to an action subresource. It gets a copy of the stored parent object and
+// the request body. Changes to obj are persisted, and the returned object is sent as response. If it is nil,
+// the updated parent object is sent.
+type ActionFunc[E resource.Object, R runtime.Object] func(ctx context.Context, obj E, req R) (runtime.Object, error)
+
+// ActionSubResource returns a SubResourceFn of a subresource that runs fn on POST requests, e.g. to restart or
+// roll back the parent object. newRequest returns an empty request body, which must be registered in the scheme
+// for the versions of the resource. Like policy/v1 Eviction, request types should have object metadata.
+//
+// fn runs within the update of the parent object and is retried on conflicts, so it should not have side effects
+// besides changing obj. Admission sees the request as a create of the subresource, and the update of the parent
+// object is validated by the strategy of the resource.
+func ActionSubResource[E resource.Object, R runtime.Object](newRequest func() R, fn ActionFunc[E, R]) SubResourceFn {
+	return func(store *genericregistry.Store) rest.Storage {
+		actionStore := *store
+		// Actions may change any field, e.g. the status, so the update is not normalized.
+		actionStore.UpdateStrategy = &rest.PrepareForUpdaterStrategy{RESTUpdateStrategy: store.UpdateStrategy}
+		return &actionStorage[E, R]{store: &actionStore, newRequest: newRequest, fn: fn}
+	}
+}
+
+var _ registryrest.NamedCreater = &actionStorage[resource.Object, runtime.Object]{}
+
+// actionStorage is the storage of a subresource created by ActionSubResource.
+type actionStorage[E resource.Object, R runtime.Object] struct {
+	store      *genericregistry.Store
+	newRequest func() R
+	fn         ActionFunc[E, R]
+}
+
+// New returns an empty request body.
+func (s *actionStorage[E, R]) New() runtime.Object {
+	return s.newRequest()
+}
+
+// Destroy does nothing, the storage is shared with and destroyed by the parent resource.
+func (s *actionStorage[E, R]) Destroy() {}
+
+// Create runs the action on the parent object with the given name.
+func (s *actionStorage[E, R]) Create(ctx context.Context, name string, obj runtime.Object, createValidation registryrest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	req, ok := obj.(R)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unexpected request of type %T", obj))
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp runtime.Object
+	action := func(ctx context.Context, _, old runtime.Object) (runtime.Object, error) {
+		parent, ok := old.DeepCopyObject().(E)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object of type %T", old)
+		}
+		var err error
+		resp, err = s.fn(ctx, parent, req)
+		return parent, err
+	}
+	updated, _, err := s.store.Update(ctx, name, registryrest.DefaultUpdatedObjectInfo(nil, action),
+		registryrest.ValidateAllObjectFunc, registryrest.ValidateAllObjectUpdateFunc, false,
+		&metav1.UpdateOptions{DryRun: options.DryRun, FieldManager: options.FieldManager})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return updated, nil
+	}
+	return resp, nil
+}
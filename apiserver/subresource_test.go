@@ -0,0 +1,118 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = Describe("Subresources", func() {
+	var (
+		ctx     context.Context
+		widgets dynamic.ResourceInterface
+	)
+
+	restart := func(name, reason string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(testGroupVersion.String())
+		obj.SetKind("WidgetRestart")
+		obj.SetName(name)
+		Expect(unstructured.SetNestedField(obj.Object, reason, "reason")).To(Succeed())
+		return obj
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rh := Resource[*Widget](&Widget{}, testGroupVersion).
+			WithSubResource("approval", FieldSubResource[*Widget](func(from, to *Widget) {
+				to.Spec.Approved = from.Spec.Approved
+			})).
+			WithSubResource("restart", ActionSubResource(func() *WidgetRestart { return &WidgetRestart{} },
+				func(ctx context.Context, obj *Widget, req *WidgetRestart) (runtime.Object, error) {
+					if !obj.Spec.Approved {
+						return nil, apierrors.NewForbidden(obj.GetGroupResource(), obj.Name, nil)
+					}
+					obj.Status.Phase = "Restarting: " + req.Reason
+					return nil, nil
+				}))
+		server := startTestServer(newWidgetTestBuilder(rh))
+		DeferCleanup(server.Stop)
+		widgets = widgetClient(server)
+	})
+
+	It("should only update the fields of a field subresource through it", func() {
+		created, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(unstructured.SetNestedField(created.Object, true, "spec", "approved")).To(Succeed())
+		updated, err := widgets.Update(ctx, created, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).ToNot(HaveKeyWithValue("spec", HaveKey("approved")))
+
+		Expect(unstructured.SetNestedField(updated.Object, true, "spec", "approved")).To(Succeed())
+		Expect(unstructured.SetNestedField(updated.Object, int64(2), "spec", "size")).To(Succeed())
+		updated, err = widgets.Update(ctx, updated, metav1.UpdateOptions{}, "approval")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object).To(HaveKeyWithValue("spec", And(
+			HaveKeyWithValue("approved", true),
+			HaveKeyWithValue("size", BeEquivalentTo(1)))))
+
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{}, "approval")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("approved", true)))
+	})
+
+	It("should run actions on the parent object and persist the changes", func() {
+		_, err := widgets.Create(ctx, newWidget("foo", 1), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = widgets.Create(ctx, restart("foo", "test"), metav1.CreateOptions{}, "restart")
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+
+		approved, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedField(approved.Object, true, "spec", "approved")).To(Succeed())
+		_, err = widgets.Update(ctx, approved, metav1.UpdateOptions{}, "approval")
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := widgets.Create(ctx, restart("foo", "test"), metav1.CreateOptions{}, "restart")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.GetKind()).To(Equal("Widget"))
+		Expect(resp.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Restarting: test")))
+
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetResourceVersion()).To(Equal(resp.GetResourceVersion()))
+		Expect(got.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Restarting: test")))
+
+		_, err = widgets.Create(ctx, restart("bar", "test"), metav1.CreateOptions{}, "restart")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+	})
+
+	It("should not persist actions of dry runs", func() {
+		approved := newWidget("foo", 1)
+		Expect(unstructured.SetNestedField(approved.Object, true, "spec", "approved")).To(Succeed())
+		created, err := widgets.Create(ctx, approved, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = widgets.Update(ctx, created, metav1.UpdateOptions{}, "approval")
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := widgets.Create(ctx, restart("foo", "test"), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}, "restart")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Restarting: test")))
+
+		got, err := widgets.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.Object).To(HaveKeyWithValue("status", BeEmpty()))
+	})
+})
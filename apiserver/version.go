@@ -0,0 +1,209 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"fmt"
+	"maps"
+
+	"go.opendefense.cloud/kit/apiserver/resource"
+	"go.opendefense.cloud/kit/apiserver/rest"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/versioning"
+	"k8s.io/apimachinery/pkg/util/diff"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/server"
+	openapiutil "k8s.io/kube-openapi/pkg/util"
+)
+
+// ResourceVersion is a version of a multi-version resource that is served with its own types, see Version.
+type ResourceVersion struct {
+	groupVersion schema.GroupVersion
+	obj          runtime.Object
+	list         runtime.Object
+	// addConversionFuncs registers the conversions between the types of the version and hub in scheme.
+	addConversionFuncs func(scheme *runtime.Scheme, hub resource.Object) error
+}
+
+// Version returns a version of a multi-version resource with hub type H, which is served in gv with the types
+// of obj and list. They must be registered in the scheme with the kinds of the hub type in gv. Objects are
+// converted from and to the hub with the methods of obj, lists item by item.
+func Version[H resource.Object, C resource.Convertible[H]](gv schema.GroupVersion, obj C, list runtime.Object) ResourceVersion {
+	return ResourceVersion{
+		groupVersion: gv,
+		obj:          obj,
+		list:         list,
+		addConversionFuncs: func(scheme *runtime.Scheme, hub resource.Object) error {
+			if _, ok := hub.(H); !ok {
+				return fmt.Errorf("version %s of %T converts to %T instead of the hub %T", gv, obj, *new(H), hub)
+			}
+			newObj := func() (C, error) {
+				kinds, _, err := scheme.ObjectKinds(obj)
+				if err != nil {
+					return *new(C), err
+				}
+				o, err := scheme.New(gv.WithKind(kinds[0].Kind))
+				if err != nil {
+					return *new(C), err
+				}
+				return o.(C), nil
+			}
+			errs := []error{
+				scheme.AddConversionFunc(obj, hub, func(a, b interface{}, _ conversion.Scope) error {
+					return a.(C).ConvertTo(b.(H))
+				}),
+				scheme.AddConversionFunc(hub, obj, func(a, b interface{}, _ conversion.Scope) error {
+					return b.(C).ConvertFrom(a.(H))
+				}),
+				scheme.AddConversionFunc(list, hub.NewList(), func(a, b interface{}, _ conversion.Scope) error {
+					return convertList(a.(runtime.Object), b.(runtime.Object), func(item runtime.Object) (runtime.Object, error) {
+						out := hub.New().(H)
+						return out, item.(C).ConvertTo(out)
+					})
+				}),
+				scheme.AddConversionFunc(hub.NewList(), list, func(a, b interface{}, _ conversion.Scope) error {
+					return convertList(a.(runtime.Object), b.(runtime.Object), func(item runtime.Object) (runtime.Object, error) {
+						out, err := newObj()
+						if err != nil {
+							return nil, err
+						}
+						return out, out.ConvertFrom(item.(H))
+					})
+				}),
+			}
+			return utilerrors.NewAggregate(errs)
+		},
+	}
+}
+
+// convertList converts the list in to the list out, converting the items with convert.
+func convertList(in, out runtime.Object, convert func(item runtime.Object) (runtime.Object, error)) error {
+	inMeta, err := meta.ListAccessor(in)
+	if err != nil {
+		return err
+	}
+	outMeta, err := meta.ListAccessor(out)
+	if err != nil {
+		return err
+	}
+	outMeta.SetResourceVersion(inMeta.GetResourceVersion())
+	outMeta.SetContinue(inMeta.GetContinue())
+	outMeta.SetRemainingItemCount(inMeta.GetRemainingItemCount())
+
+	items, err := meta.ExtractList(in)
+	if err != nil {
+		return err
+	}
+	converted := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		c, err := convert(item)
+		if err != nil {
+			return err
+		}
+		converted = append(converted, c)
+	}
+	return meta.SetList(out, converted)
+}
+
+// WithVersions returns a copy of the ResourceHandler that also serves the resource in the given versions
+// with their own types, which are converted from and to the type passed to Resource, the hub. Objects of all
+// versions are stored in the first version passed to Resource. When the server is built, the object passed to
+// Resource must round-trip through every served version, so it can be populated to check that conversions
+// are lossless.
+func (rh ResourceHandler) WithVersions(versions ...ResourceVersion) ResourceHandler {
+	if len(rh.groupVersions) == 0 {
+		panic(fmt.Sprintf("resource %s has no version of its hub type", rh.groupResource))
+	}
+	if rh.storageVersion.Empty() {
+		rh.storageVersion = rh.groupVersions[0]
+	}
+	rh.versions = append(append([]ResourceVersion{}, rh.versions...), versions...)
+	rh.groupVersions = append([]schema.GroupVersion{}, rh.groupVersions...)
+	for _, v := range versions {
+		rh.groupVersions = append(rh.groupVersions, v.groupVersion)
+		// Objects of all versions are hub objects in storage, so the hub's selectable fields apply.
+		if fields, ok := rh.selectableFields[openapiutil.GetCanonicalTypeName(rh.hub)]; ok {
+			rh.selectableFields = maps.Clone(rh.selectableFields)
+			rh.selectableFields[openapiutil.GetCanonicalTypeName(v.obj)] = fields
+			rh.selectableFields[openapiutil.GetCanonicalTypeName(v.list)] = fields
+		}
+	}
+	return rh
+}
+
+// withVersions wraps fn to serve the storage of the resource in the versions added with WithVersions.
+func (rh ResourceHandler) withVersions(fn APIGroupFn) APIGroupFn {
+	if len(rh.versions) == 0 {
+		return fn
+	}
+	return func(scheme *runtime.Scheme, codecs serializer.CodecFactory, c *server.CompletedConfig) server.APIGroupInfo {
+		apiGroupInfo := fn(scheme, codecs, c)
+		storage := apiGroupInfo.VersionedResourcesStorageMap[rh.storageVersion.Version]
+		for _, v := range rh.versions {
+			apiGroupInfo.VersionedResourcesStorageMap[v.groupVersion.Version] = storage
+		}
+		return apiGroupInfo
+	}
+}
+
+// addConversions registers the conversions of the versions added with WithVersions and checks that the hub
+// object passed to Resource round-trips through every served version.
+func (rh ResourceHandler) addConversions(scheme *runtime.Scheme, codecs serializer.CodecFactory) error {
+	errs := []error{}
+	for _, v := range rh.versions {
+		if err := v.addConversionFuncs(scheme, rh.hub); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := rest.AddFieldLabelConversionFuncs(scheme, rh.hub, v.groupVersion); err != nil {
+			errs = append(errs, err)
+		}
+		rest.AddDefaultingFuncs(scheme, v.obj, v.list)
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	sample := rh.hub.DeepCopyObject()
+	list := rh.hub.NewList()
+	if err := meta.SetList(list, []runtime.Object{sample.DeepCopyObject()}); err != nil {
+		return err
+	}
+	for _, gv := range rh.groupVersions {
+		for _, obj := range []runtime.Object{sample, list} {
+			if err := roundTrip(scheme, codecs, obj, gv); err != nil {
+				errs = append(errs, fmt.Errorf("resource %s: %w", rh.groupResource, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// roundTrip encodes obj in gv and decodes it to the internal version, and fails if it changed. Defaults are
+// not applied when decoding, so they cannot hide lossy conversions.
+func roundTrip(scheme *runtime.Scheme, codecs serializer.CodecFactory, obj runtime.Object, gv schema.GroupVersion) error {
+	data, err := runtime.Encode(codecs.LegacyCodec(gv), obj)
+	if err != nil {
+		return fmt.Errorf("failed to encode %T in %s: %w", obj, gv, err)
+	}
+	internal := schema.GroupVersion{Group: gv.Group, Version: runtime.APIVersionInternal}
+	decoder := versioning.NewCodec(nil, codecs.UniversalDeserializer(), runtime.UnsafeObjectConvertor(scheme), scheme, scheme, nil, nil, internal, scheme.Name())
+	decoded, err := runtime.Decode(decoder, data)
+	if err != nil {
+		return fmt.Errorf("failed to decode %T from %s: %w", obj, gv, err)
+	}
+
+	expected := obj.DeepCopyObject()
+	expected.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	decoded.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	if !equality.Semantic.DeepEqual(expected, decoded) {
+		return fmt.Errorf("%T does not round-trip through %s: %s", obj, gv, diff.Diff(expected, decoded))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 BWI GmbH and Artifact Conduit contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apiserver
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.opendefense.cloud/kit/apiserver/storage/sqlite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// testGroupVersionV1alpha1 is the group version of the v1alpha1 version of widgets.
+var testGroupVersionV1alpha1 = schema.GroupVersion{Group: testGroupVersion.Group, Version: "v1alpha1"}
+
+// WidgetV1alpha1 is the v1alpha1 version of Widget, which calls the size dimension and cannot be approved.
+type WidgetV1alpha1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WidgetV1alpha1Spec `json:"spec,omitempty"`
+	Status            WidgetStatus       `json:"status,omitempty"`
+}
+
+type WidgetV1alpha1Spec struct {
+	Dimension int32 `json:"dimension,omitempty"`
+	Replicas  int32 `json:"replicas,omitempty"`
+}
+
+func (w *WidgetV1alpha1) DeepCopyObject() runtime.Object {
+	out := *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+func (w *WidgetV1alpha1) ConvertTo(hub *Widget) error {
+	w.ObjectMeta.DeepCopyInto(&hub.ObjectMeta)
+	hub.Spec = WidgetSpec{Size: w.Spec.Dimension, Replicas: w.Spec.Replicas}
+	hub.Status = w.Status
+	return nil
+}
+
+func (w *WidgetV1alpha1) ConvertFrom(hub *Widget) error {
+	hub.ObjectMeta.DeepCopyInto(&w.ObjectMeta)
+	w.Spec = WidgetV1alpha1Spec{Dimension: hub.Spec.Size, Replicas: hub.Spec.Replicas}
+	w.Status = hub.Status
+	return nil
+}
+
+type WidgetV1alpha1List struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WidgetV1alpha1 `json:"items"`
+}
+
+func (l *WidgetV1alpha1List) DeepCopyObject() runtime.Object {
+	out := &WidgetV1alpha1List{TypeMeta: l.TypeMeta}
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	out.Items = make([]WidgetV1alpha1, len(l.Items))
+	for i := range l.Items {
+		out.Items[i] = *l.Items[i].DeepCopyObject().(*WidgetV1alpha1)
+	}
+	return out
+}
+
+// newMultiVersionWidgetTestBuilder returns a test Builder that serves widgets in v1 and v1alpha1 with the given
+// ResourceHandler, and stores them in a SQLite database at path.
+func newMultiVersionWidgetTestBuilder(rh ResourceHandler, path string) *Builder {
+	b := newWidgetTestBuilder(rh).WithSQLiteStorage(path)
+	b.scheme.AddKnownTypeWithName(testGroupVersionV1alpha1.WithKind("Widget"), &WidgetV1alpha1{})
+	b.scheme.AddKnownTypeWithName(testGroupVersionV1alpha1.WithKind("WidgetList"), &WidgetV1alpha1List{})
+	metav1.AddToGroupVersion(b.scheme, testGroupVersionV1alpha1)
+	// The v1alpha1 version is preferred, widgets must still be stored in the v1 version of the hub.
+	utilruntime.Must(b.scheme.SetVersionPriority(testGroupVersionV1alpha1, testGroupVersion))
+	return b
+}
+
+var _ = Describe("Multi-version resources", func() {
+	var (
+		ctx  context.Context
+		path string
+		rh   ResourceHandler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "test.db")
+		rh = Resource[*Widget](&Widget{}, testGroupVersion).
+			WithVersions(Version[*Widget](testGroupVersionV1alpha1, &WidgetV1alpha1{}, &WidgetV1alpha1List{}))
+	})
+
+	It("should serve the resource in every version and store it in the hub version", func() {
+		server := startTestServer(newMultiVersionWidgetTestBuilder(rh, path))
+		DeferCleanup(server.Stop)
+		client, err := dynamic.NewForConfig(server.GenericAPIServer.LoopbackClientConfig)
+		Expect(err).ToNot(HaveOccurred())
+		v1 := widgetClient(server)
+		v1alpha1 := client.Resource(testGroupVersionV1alpha1.WithResource("widgets")).Namespace(metav1.NamespaceDefault)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(testGroupVersionV1alpha1.String())
+		obj.SetKind("Widget")
+		obj.SetName("foo")
+		Expect(unstructured.SetNestedField(obj.Object, int64(3), "spec", "dimension")).To(Succeed())
+		_, err = v1alpha1.Create(ctx, obj, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		got, err := v1.Get(ctx, "foo", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got.GetAPIVersion()).To(Equal(testGroupVersion.String()))
+		Expect(got.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("size", BeEquivalentTo(3))))
+		// The defaults of the hub apply to objects created in other versions.
+		Expect(got.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("color", "blue")))
+
+		_, err = v1.Create(ctx, newWidget("bar", 5), metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		list, err := v1alpha1.List(ctx, metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.GetAPIVersion()).To(Equal(testGroupVersionV1alpha1.String()))
+		Expect(list.Items).To(HaveLen(2))
+		for _, item := range list.Items {
+			Expect(item.GetAPIVersion()).To(Equal(testGroupVersionV1alpha1.String()))
+			Expect(item.Object).To(HaveKeyWithValue("spec", HaveKey("dimension")))
+		}
+
+		selected, err := v1alpha1.List(ctx, metav1.ListOptions{FieldSelector: "spec.size=5"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selected.Items).To(HaveLen(1))
+		Expect(selected.Items[0].GetName()).To(Equal("bar"))
+
+		Expect(server.Stop()).To(Succeed())
+		backend, err := sqlite.New(path)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(backend.Close)
+		kvs, _, _, err := backend.List(ctx, "/registry/", "", 0, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(kvs).To(HaveLen(2))
+		for _, kv := range kvs {
+			Expect(string(kv.Value)).To(ContainSubstring(`"apiVersion":"` + testGroupVersion.String() + `"`))
+		}
+	})
+
+	It("should fail to build if the hub does not round-trip through a version", func() {
+		rh = Resource[*Widget](&Widget{Spec: WidgetSpec{Approved: true}}, testGroupVersion).
+			WithVersions(Version[*Widget](testGroupVersionV1alpha1, &WidgetV1alpha1{}, &WidgetV1alpha1List{}))
+		_, err := newMultiVersionWidgetTestBuilder(rh, path).Build(ctx, nil)
+		Expect(err).To(MatchError(ContainSubstring("does not round-trip through " + testGroupVersionV1alpha1.String())))
+	})
+
+	It("should fail to build if a version is not registered", func() {
+		gv := schema.GroupVersion{Group: testGroupVersion.Group, Version: "v1beta1"}
+		rh = rh.WithVersions(Version[*Widget](gv, &WidgetV1alpha1{}, &WidgetV1alpha1List{}))
+		_, err := newMultiVersionWidgetTestBuilder(rh, path).Build(ctx, nil)
+		Expect(err).To(MatchError(ContainSubstring("failed to encode")))
+	})
+
+	It("should return copies with the versions", func() {
+		Expect(rh.groupVersions).To(Equal([]schema.GroupVersion{testGroupVersion, testGroupVersionV1alpha1}))
+		Expect(rh.storageVersion).To(Equal(testGroupVersion))
+		Expect(rh.selectableFields).To(HaveKey("go.opendefense.cloud/kit/apiserver.WidgetV1alpha1"))
+		Expect(rh.selectableFields).To(HaveKey("go.opendefense.cloud/kit/apiserver.WidgetV1alpha1List"))
+	})
+})